@@ -2,20 +2,52 @@ package config
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"strings"
+	"time"
 
+	"dbMonitor/internal/schedule"
+	"dbMonitor/internal/templates"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Databases   []DatabaseConfig  `yaml:"databases"`
-	Email       EmailConfig       `yaml:"email"`
-	Slack       SlackConfig       `yaml:"slack"`
-	Thresholds  ThresholdConfig   `yaml:"thresholds"`
-	Pool        PoolConfig        `yaml:"pool"`
-	Application ApplicationConfig `yaml:"application"`
+	Databases         []DatabaseConfig        `yaml:"databases"`
+	Email             EmailConfig             `yaml:"email"`
+	Slack             SlackConfig             `yaml:"slack"`
+	Teams             TeamsConfig             `yaml:"teams"`
+	Webhooks          []WebhookConfig         `yaml:"webhooks"`
+	PagerDuty         PagerDutyConfig         `yaml:"pagerduty"`
+	Syslog            SyslogConfig            `yaml:"syslog"`
+	NotificationQueue NotificationQueueConfig `yaml:"notification_queue"`
+	Thresholds        ThresholdConfig         `yaml:"thresholds"`
+	Pool              PoolConfig              `yaml:"pool"`
+	Application       ApplicationConfig       `yaml:"application"`
+
+	// Channels ajusta timeout e fallback de cada canal pelo nome: email,
+	// slack, teams, pagerduty, syslog ou webhook-<nome>.
+	Channels map[string]ChannelConfig `yaml:"channels"`
+
+	// Route e Receivers definem a árvore de roteamento. Sem route, cada canal
+	// recebe os alertas das severidades listadas na própria seção.
+	Route     *RouteConfig     `yaml:"route"`
+	Receivers []ReceiverConfig `yaml:"receivers"`
+
+	// Janelas de manutenção recorrentes, tratadas como silences.
+	MaintenanceWindows []MaintenanceWindowConfig `yaml:"maintenance_windows"`
+
+	// Templates por canal (email, slack, teams) e tipo de alerta, com "default"
+	// como fallback. Canais sem templates usam o formato padrão do notifier.
+	Templates map[string]map[string]templates.Files `yaml:"templates"`
+
+	alertTemplates *templates.Set
 }
 
+// templateChannels são os canais que aceitam templates; html vale apenas para
+// o email.
+var templateChannels = map[string]bool{"email": true, "slack": true, "teams": true}
+
 type DatabaseConfig struct {
 	Name           string `yaml:"name"`
 	Type           string `yaml:"type"`
@@ -28,26 +60,387 @@ type DatabaseConfig struct {
 	CertPath       string `yaml:"cert_path"`
 	ConnectTimeout int    `yaml:"connect_timeout"`
 	QueryTimeout   int    `yaml:"query_timeout"`
+
+	Labels map[string]string `yaml:"labels"`
+
+	// Sobrescrevem os valores globais de thresholds e application.repeat_interval
+	// para esta base. Métricas não informadas herdam o valor global.
+	Thresholds     ThresholdConfig `yaml:"thresholds"`
+	RepeatInterval int             `yaml:"repeat_interval"`
+
+	IdleTransactionPolicy IdleTransactionPolicy `yaml:"idle_transaction_policy"`
+}
+
+// IdleTransactionPolicy encerra (pg_terminate_backend) as sessões paradas em
+// transação há mais de TerminateAfter segundos. Só é suportada no PostgreSQL.
+type IdleTransactionPolicy struct {
+	Enabled           bool     `yaml:"enabled"`
+	TerminateAfter    int      `yaml:"terminate_after"`
+	AllowUsers        []string `yaml:"allow_users"`
+	AllowApplications []string `yaml:"allow_applications"`
+	DryRun            bool     `yaml:"dry_run"`
+}
+
+// IsAllowed indica se a sessão está na allow-list e não pode ser encerrada.
+func (p IdleTransactionPolicy) IsAllowed(user, application string) bool {
+	for _, u := range p.AllowUsers {
+		if u == user {
+			return true
+		}
+	}
+	for _, a := range p.AllowApplications {
+		if a == application {
+			return true
+		}
+	}
+	return false
 }
 
 type EmailConfig struct {
-	SMTPHost  string   `yaml:"smtp_host"`
-	SMTPPort  int      `yaml:"smtp_port"`
-	Username  string   `yaml:"username"`
-	Password  string   `yaml:"password"`
-	FromEmail string   `yaml:"from_email"`
-	ToEmails  []string `yaml:"to_emails"`
-	UseTLS    bool     `yaml:"use_tls"`
+	SMTPHost   string   `yaml:"smtp_host"`
+	SMTPPort   int      `yaml:"smtp_port"`
+	Username   string   `yaml:"username"`
+	Password   string   `yaml:"password"`
+	FromEmail  string   `yaml:"from_email"`
+	ToEmails   []string `yaml:"to_emails"`
+	UseTLS     bool     `yaml:"use_tls"`
+	Severities []string `yaml:"severities"`
+
+	// TLSMode tem precedência sobre UseTLS: none, starttls (oportunista),
+	// starttls_required ou tls (implícito, normalmente na porta 465).
+	TLSMode       string `yaml:"tls_mode"`
+	CAFile        string `yaml:"ca_file"`
+	ClientCert    string `yaml:"client_cert"`
+	ClientKey     string `yaml:"client_key"`
+	ServerName    string `yaml:"server_name"`
+	AuthMechanism string `yaml:"auth_mechanism"`
+}
+
+const (
+	SMTPTLSNone             = "none"
+	SMTPTLSStartTLS         = "starttls"
+	SMTPTLSStartTLSRequired = "starttls_required"
+	SMTPTLSImplicit         = "tls"
+)
+
+// EffectiveTLSMode resolve o modo de TLS do SMTP. Sem tls_mode, a porta 465
+// usa TLS implícito, use_tls exige STARTTLS e, caso contrário, o STARTTLS é
+// usado apenas quando o servidor o anuncia.
+func (e EmailConfig) EffectiveTLSMode() string {
+	switch {
+	case e.TLSMode != "":
+		return e.TLSMode
+	case e.SMTPPort == 465:
+		return SMTPTLSImplicit
+	case e.UseTLS:
+		return SMTPTLSStartTLSRequired
+	default:
+		return SMTPTLSStartTLS
+	}
 }
 
+func (e EmailConfig) validate() error {
+	switch e.EffectiveTLSMode() {
+	case SMTPTLSNone, SMTPTLSStartTLS, SMTPTLSStartTLSRequired, SMTPTLSImplicit:
+	default:
+		return fmt.Errorf("tls_mode inválido: %s", e.TLSMode)
+	}
+
+	switch strings.ToUpper(e.AuthMechanism) {
+	case "", "PLAIN", "LOGIN", "CRAM-MD5":
+	default:
+		return fmt.Errorf("auth_mechanism inválido: %s", e.AuthMechanism)
+	}
+
+	if (e.ClientCert == "") != (e.ClientKey == "") {
+		return fmt.Errorf("client_cert e client_key devem ser informados juntos")
+	}
+
+	return nil
+}
+
+// SlackConfig aceita um incoming webhook ou, com BotToken e Channel, o modo
+// Web API, que agrupa repetições e a resolução na thread do alerta.
 type SlackConfig struct {
-	WebhookURL string `yaml:"webhook_url"`
+	WebhookURL string   `yaml:"webhook_url"`
+	BotToken   string   `yaml:"bot_token"`
+	Channel    string   `yaml:"channel"`
+	APIURL     string   `yaml:"api_url"`
+	Severities []string `yaml:"severities"`
+}
+
+// TeamsConfig configura o incoming webhook do Microsoft Teams. DashboardURL
+// é o endereço público do servidor HTTP do dbMonitor, usado no link do card.
+type TeamsConfig struct {
+	WebhookURL   string   `yaml:"webhook_url"`
+	DashboardURL string   `yaml:"dashboard_url"`
+	Severities   []string `yaml:"severities"`
+}
+
+// PagerDutyConfig configura a Events API v2. APIURL permite apontar para um
+// servidor local em testes; vazio usa https://events.pagerduty.com.
+type PagerDutyConfig struct {
+	RoutingKey string   `yaml:"routing_key"`
+	APIURL     string   `yaml:"api_url"`
+	Severities []string `yaml:"severities"`
+}
+
+// WebhookConfig descreve um endpoint HTTP genérico que recebe os alertas.
+// Sem body_template, o corpo é o alerta serializado em JSON. Com secret, o
+// corpo é assinado com HMAC-SHA256 no cabeçalho signature_header.
+type WebhookConfig struct {
+	Name                string            `yaml:"name"`
+	URL                 string            `yaml:"url"`
+	Method              string            `yaml:"method"`
+	Headers             map[string]string `yaml:"headers"`
+	BodyTemplate        string            `yaml:"body_template"`
+	Secret              string            `yaml:"secret"`
+	SignatureHeader     string            `yaml:"signature_header"`
+	Timeout             int               `yaml:"timeout"`
+	AcceptedStatusCodes []int             `yaml:"accepted_status_codes"`
+	Severities          []string          `yaml:"severities"`
+}
+
+func (w WebhookConfig) validate() error {
+	if w.URL == "" {
+		return fmt.Errorf("url não pode estar vazia")
+	}
+
+	switch strings.ToUpper(w.Method) {
+	case "", "POST", "PUT", "PATCH":
+	default:
+		return fmt.Errorf("método HTTP inválido: %s", w.Method)
+	}
+
+	if w.Timeout < 0 {
+		return fmt.Errorf("timeout não pode ser negativo")
+	}
+
+	for _, code := range w.AcceptedStatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("status HTTP inválido: %d", code)
+		}
+	}
+
+	if err := validateSeverities(w.Severities); err != nil {
+		return err
+	}
+
+	if w.BodyTemplate != "" {
+		if _, err := templates.LoadText(w.BodyTemplate); err != nil {
+			return fmt.Errorf("body_template inválido: %w", err)
+		}
+	}
+
+	return nil
 }
 
 type ThresholdConfig struct {
-	ActiveConnections   int `yaml:"active_connections"`
-	InactiveConnections int `yaml:"inactive_connections"`
-	TotalConnections    int `yaml:"total_connections"`
+	ActiveConnections   ThresholdLevels `yaml:"active_connections"`
+	InactiveConnections ThresholdLevels `yaml:"inactive_connections"`
+	TotalConnections    ThresholdLevels `yaml:"total_connections"`
+
+	// Thresholds em percentual do max_connections lido do servidor a cada
+	// verificação. Quando combinados com os absolutos, vale o mais restritivo;
+	// clear vem do lado cujo nível de disparo foi escolhido e for/for_checks,
+	// do maior dos dois.
+	ActiveConnectionsPct   ThresholdLevels `yaml:"active_connections_pct"`
+	InactiveConnectionsPct ThresholdLevels `yaml:"inactive_connections_pct"`
+	TotalConnectionsPct    ThresholdLevels `yaml:"total_connections_pct"`
+
+	ReplicationLagSeconds ThresholdLevels `yaml:"replication_lag_seconds"`
+	ReplicationLagBytes   ThresholdLevels `yaml:"replication_lag_bytes"`
+
+	LongRunningQuerySeconds ThresholdLevels `yaml:"long_running_query_seconds"`
+	BlockedSessionSeconds   ThresholdLevels `yaml:"blocked_session_seconds"`
+
+	IdleInTransactionSeconds ThresholdLevels `yaml:"idle_in_transaction_seconds"`
+}
+
+// ThresholdLevels define os níveis de warning e critical de uma métrica.
+// Um nível igual a zero fica desativado. Por compatibilidade, um valor
+// inteiro simples no YAML é tratado como o nível critical.
+//
+// For (segundos) e ForChecks (verificações consecutivas) exigem que a violação
+// se sustente antes de disparar; com os dois, ambos precisam ser atingidos.
+// Clear define a histerese: um alerta disparado só é resolvido quando o valor
+// fica abaixo de Clear, e não apenas abaixo do nível que o disparou.
+type ThresholdLevels struct {
+	Warning   int `yaml:"warning"`
+	Critical  int `yaml:"critical"`
+	Clear     int `yaml:"clear"`
+	For       int `yaml:"for"`
+	ForChecks int `yaml:"for_checks"`
+}
+
+func (t *ThresholdLevels) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var critical int
+		if err := value.Decode(&critical); err != nil {
+			return err
+		}
+		*t = ThresholdLevels{Critical: critical}
+		return nil
+	}
+
+	type plain ThresholdLevels
+	return value.Decode((*plain)(t))
+}
+
+// Lowest retorna o menor nível ativo, ou zero se nenhum estiver definido.
+func (t ThresholdLevels) Lowest() int {
+	if t.Warning > 0 && (t.Critical == 0 || t.Warning < t.Critical) {
+		return t.Warning
+	}
+	return t.Critical
+}
+
+func (t ThresholdLevels) isSet() bool {
+	return t.Warning != 0 || t.Critical != 0
+}
+
+// merge aplica a sobrescrita de uma base. Warning, critical e clear são
+// substituídos juntos, já que clear depende dos níveis; for e for_checks,
+// individualmente.
+func (t ThresholdLevels) merge(override ThresholdLevels) ThresholdLevels {
+	if override.isSet() {
+		t.Warning = override.Warning
+		t.Critical = override.Critical
+		t.Clear = override.Clear
+	} else if override.Clear != 0 {
+		t.Clear = override.Clear
+	}
+	if override.For != 0 {
+		t.For = override.For
+	}
+	if override.ForChecks != 0 {
+		t.ForChecks = override.ForChecks
+	}
+	return t
+}
+
+func (t ThresholdLevels) validate() error {
+	if t.Warning < 0 || t.Critical < 0 || t.Clear < 0 || t.For < 0 || t.ForChecks < 0 {
+		return fmt.Errorf("níveis não podem ser negativos")
+	}
+	if t.Warning > 0 && t.Critical > 0 && t.Warning > t.Critical {
+		return fmt.Errorf("warning (%d) não pode ser maior que critical (%d)", t.Warning, t.Critical)
+	}
+	if t.Clear > 0 && t.isSet() && t.Clear >= t.Lowest() {
+		return fmt.Errorf("clear (%d) deve ser menor que o menor nível (%d)", t.Clear, t.Lowest())
+	}
+	return nil
+}
+
+// SyslogConfig configura o canal de syslog. Sem network e address, usa o
+// syslog local. Com fallback_only, o canal só recebe alertas como fallback.
+type SyslogConfig struct {
+	Enabled      bool     `yaml:"enabled"`
+	Network      string   `yaml:"network"`
+	Address      string   `yaml:"address"`
+	Tag          string   `yaml:"tag"`
+	FallbackOnly bool     `yaml:"fallback_only"`
+	Severities   []string `yaml:"severities"`
+}
+
+// ChannelConfig define o timeout de entrega (em segundos) e o canal usado
+// quando a entrega falha. Fallbacks podem ser encadeados.
+type ChannelConfig struct {
+	Timeout  int    `yaml:"timeout"`
+	Fallback string `yaml:"fallback"`
+}
+
+// RouteConfig é um nó da árvore de roteamento. Um alerta segue para a
+// primeira rota filha que casar (ou para todas as que casarem, enquanto
+// continue for true); sem nenhuma filha, vai para os receivers do nó.
+type RouteConfig struct {
+	Match     RouteMatch    `yaml:"match"`
+	Receivers []string      `yaml:"receivers"`
+	Continue  bool          `yaml:"continue"`
+	Routes    []RouteConfig `yaml:"routes"`
+}
+
+// RouteMatch casa quando todos os critérios informados casam. Em cada lista,
+// basta um dos valores; em Labels, todos os pares precisam estar nos labels
+// da base.
+type RouteMatch struct {
+	Databases  []string          `yaml:"databases"`
+	Labels     map[string]string `yaml:"labels"`
+	AlertTypes []string          `yaml:"alert_types"`
+	Severities []string          `yaml:"severities"`
+}
+
+func (m RouteMatch) Matches(database, alertType, severity string, labels map[string]string) bool {
+	if len(m.Databases) > 0 && !contains(m.Databases, database) {
+		return false
+	}
+	if len(m.AlertTypes) > 0 && !contains(m.AlertTypes, alertType) {
+		return false
+	}
+	if len(m.Severities) > 0 && !contains(m.Severities, severity) {
+		return false
+	}
+	for k, v := range m.Labels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ReceiverConfig agrupa canais sob um nome usado nas rotas.
+type ReceiverConfig struct {
+	Name     string   `yaml:"name"`
+	Channels []string `yaml:"channels"`
+}
+
+// MaintenanceWindowConfig silencia os alertas que casarem com os critérios
+// durante Duration minutos a partir de cada horário que casar com Schedule
+// (expressão cron de cinco campos, no fuso Timezone ou no horário local).
+type MaintenanceWindowConfig struct {
+	Name       string            `yaml:"name"`
+	Schedule   string            `yaml:"schedule"`
+	Duration   int               `yaml:"duration"`
+	Timezone   string            `yaml:"timezone"`
+	Databases  []string          `yaml:"databases"`
+	AlertTypes []string          `yaml:"alert_types"`
+	Labels     map[string]string `yaml:"labels"`
+	Comment    string            `yaml:"comment"`
+}
+
+func (w MaintenanceWindowConfig) validate() error {
+	if _, err := schedule.Parse(w.Schedule); err != nil {
+		return err
+	}
+	if w.Duration <= 0 {
+		return fmt.Errorf("duration deve ser maior que zero")
+	}
+	if w.Timezone != "" {
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("timezone inválido: %w", err)
+		}
+	}
+	return nil
+}
+
+// NotificationQueueConfig controla a fila persistente de cada canal. Os
+// alertas pendentes ficam em spool_dir/<canal> e os que esgotam as
+// tentativas vão para spool_dir/<canal>/dead.
+type NotificationQueueConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	SpoolDir       string `yaml:"spool_dir"`
+	MaxAttempts    int    `yaml:"max_attempts"`
+	BackoffInitial int    `yaml:"backoff_initial"`
+	BackoffMax     int    `yaml:"backoff_max"`
 }
 
 type PoolConfig struct {
@@ -63,9 +456,70 @@ type PoolConfig struct {
 type ApplicationConfig struct {
 	MonitoringInterval  int    `yaml:"monitoring_interval"`
 	HealthCheckInterval int    `yaml:"health_check_interval"`
-	AlertResetInterval  int    `yaml:"alert_reset_interval"`
-	AlertFrequency      int    `yaml:"alert_frequency"`
 	HTTPServerAddress   string `yaml:"http_server_address"`
+
+	// Um alerta é notificado ao disparar e renotificado a cada RepeatInterval
+	// segundos enquanto continuar ativo. Com RepeatBackoff > 1, o intervalo é
+	// multiplicado a cada repetição, até MaxRepeatInterval (0 = sem limite).
+	RepeatInterval    int     `yaml:"repeat_interval"`
+	RepeatBackoff     float64 `yaml:"repeat_backoff"`
+	MaxRepeatInterval int     `yaml:"max_repeat_interval"`
+}
+
+// DefaultRepeatInterval é o repeat_interval usado quando a aplicação não o
+// configura, em segundos.
+const DefaultRepeatInterval = 3600
+
+// renamedKeys lê apenas as chaves substituídas por repeat_interval. Como o
+// yaml ignora chaves desconhecidas, sem essa leitura uma configuração antiga
+// seria aceita com um comportamento de notificação diferente do esperado.
+type renamedKeys struct {
+	Application struct {
+		AlertFrequency     *int `yaml:"alert_frequency"`
+		AlertResetInterval *int `yaml:"alert_reset_interval"`
+	} `yaml:"application"`
+	Databases []struct {
+		Name           string `yaml:"name"`
+		AlertFrequency *int   `yaml:"alert_frequency"`
+	} `yaml:"databases"`
+}
+
+// migrateRenamedKeys converte as chaves antigas em repeat_interval quando ele
+// não foi configurado, registrando um aviso de descontinuação. alert_frequency
+// notificava a cada N verificações, o que equivale a N * monitoring_interval
+// segundos; alert_reset_interval reabria a notificação a cada intervalo e só é
+// usado quando alert_frequency também não está presente.
+func (c *Config) migrateRenamedKeys(data []byte) error {
+	var keys renamedKeys
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("erro ao fazer parse da configuração: %w", err)
+	}
+
+	app := &c.Application
+	if keys.Application.AlertFrequency != nil {
+		log.Printf("Aviso: application.alert_frequency está descontinuado; use application.repeat_interval (intervalo de renotificação em segundos)")
+		if app.RepeatInterval == 0 {
+			app.RepeatInterval = *keys.Application.AlertFrequency * app.MonitoringInterval
+		}
+	}
+	if keys.Application.AlertResetInterval != nil {
+		log.Printf("Aviso: application.alert_reset_interval está descontinuado; use application.repeat_interval e, para limpar os alertas, POST /reset-alerts")
+		if app.RepeatInterval == 0 {
+			app.RepeatInterval = *keys.Application.AlertResetInterval
+		}
+	}
+
+	for i, db := range keys.Databases {
+		if db.AlertFrequency == nil || i >= len(c.Databases) {
+			continue
+		}
+		log.Printf("Aviso: alert_frequency da base %s está descontinuado; use repeat_interval (intervalo de renotificação em segundos)", db.Name)
+		if c.Databases[i].RepeatInterval == 0 {
+			c.Databases[i].RepeatInterval = *db.AlertFrequency * app.MonitoringInterval
+		}
+	}
+
+	return nil
 }
 
 func Load(configPath string) (*Config, error) {
@@ -79,6 +533,10 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("erro ao fazer parse da configuração: %w", err)
 	}
 
+	if err := config.migrateRenamedKeys(data); err != nil {
+		return nil, err
+	}
+
 	if err := config.validate(); err != nil {
 		return nil, fmt.Errorf("configuração inválida: %w", err)
 	}
@@ -101,15 +559,312 @@ func (c *Config) validate() error {
 		if db.Host == "" {
 			return fmt.Errorf("host não pode estar vazio para %s", db.Name)
 		}
+		if err := c.EffectiveThresholds(db).validate(); err != nil {
+			return fmt.Errorf("thresholds inválidos para %s: %w", db.Name, err)
+		}
+		if db.RepeatInterval < 0 {
+			return fmt.Errorf("repeat_interval não pode ser negativo para %s", db.Name)
+		}
+		if db.IdleTransactionPolicy.Enabled {
+			if db.Type != "postgresql" {
+				return fmt.Errorf("idle_transaction_policy só é suportada em postgresql (%s)", db.Name)
+			}
+			if db.IdleTransactionPolicy.TerminateAfter <= 0 {
+				return fmt.Errorf("terminate_after deve ser maior que zero para %s", db.Name)
+			}
+		}
 	}
 
 	if c.Email.SMTPHost == "" || c.Email.FromEmail == "" || len(c.Email.ToEmails) == 0 {
 		return fmt.Errorf("configuração de email incompleta")
 	}
 
-	if c.Application.MonitoringInterval == 0 || c.Application.HealthCheckInterval == 0 || c.Application.AlertResetInterval == 0 || c.Application.AlertFrequency == 0 {
+	if err := c.Thresholds.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Email.validate(); err != nil {
+		return fmt.Errorf("configuração de email inválida: %w", err)
+	}
+
+	if err := validateSeverities(c.Email.Severities); err != nil {
+		return fmt.Errorf("severidades de email inválidas: %w", err)
+	}
+	if c.Slack.BotToken != "" && c.Slack.Channel == "" {
+		return fmt.Errorf("channel é obrigatório quando slack.bot_token está configurado")
+	}
+	if err := validateSeverities(c.Slack.Severities); err != nil {
+		return fmt.Errorf("severidades do Slack inválidas: %w", err)
+	}
+
+	if err := validateSeverities(c.Teams.Severities); err != nil {
+		return fmt.Errorf("severidades do Teams inválidas: %w", err)
+	}
+	if err := validateSeverities(c.PagerDuty.Severities); err != nil {
+		return fmt.Errorf("severidades do PagerDuty inválidas: %w", err)
+	}
+
+	webhookNames := make(map[string]bool)
+	for i, w := range c.Webhooks {
+		if w.Name == "" {
+			return fmt.Errorf("nome do webhook %d não pode estar vazio", i)
+		}
+		if !validChannelName(w.Name) {
+			return fmt.Errorf("nome do webhook %s deve conter apenas letras, números, '-' e '_'", w.Name)
+		}
+		if webhookNames[w.Name] {
+			return fmt.Errorf("webhook duplicado: %s", w.Name)
+		}
+		webhookNames[w.Name] = true
+
+		if err := w.validate(); err != nil {
+			return fmt.Errorf("webhook %s inválido: %w", w.Name, err)
+		}
+	}
+
+	if err := validateSeverities(c.Syslog.Severities); err != nil {
+		return fmt.Errorf("severidades do syslog inválidas: %w", err)
+	}
+
+	if err := c.validateChannels(); err != nil {
+		return err
+	}
+
+	if err := c.validateRouting(); err != nil {
+		return err
+	}
+
+	windowNames := make(map[string]bool)
+	for i, w := range c.MaintenanceWindows {
+		if w.Name == "" {
+			return fmt.Errorf("nome da janela de manutenção %d não pode estar vazio", i)
+		}
+		if windowNames[w.Name] {
+			return fmt.Errorf("janela de manutenção duplicada: %s", w.Name)
+		}
+		windowNames[w.Name] = true
+
+		if err := w.validate(); err != nil {
+			return fmt.Errorf("janela de manutenção %s inválida: %w", w.Name, err)
+		}
+	}
+
+	if c.NotificationQueue.MaxAttempts < 0 || c.NotificationQueue.BackoffInitial < 0 || c.NotificationQueue.BackoffMax < 0 {
+		return fmt.Errorf("notification_queue não aceita valores negativos")
+	}
+
+	for channel, alertTypes := range c.Templates {
+		if !templateChannels[channel] {
+			return fmt.Errorf("templates: canal desconhecido %q (use email, slack ou teams)", channel)
+		}
+		for alertType, files := range alertTypes {
+			if files.HTML != "" && channel != "email" {
+				return fmt.Errorf("templates: html só é suportado no email (%s/%s)", channel, alertType)
+			}
+		}
+	}
+
+	alertTemplates, err := templates.Load(c.Templates)
+	if err != nil {
+		return fmt.Errorf("templates inválidos: %w", err)
+	}
+	c.alertTemplates = alertTemplates
+
+	if c.Application.MonitoringInterval == 0 || c.Application.HealthCheckInterval == 0 {
 		return fmt.Errorf("configurações de aplicação incompletas")
 	}
 
+	if c.Application.RepeatInterval < 0 {
+		return fmt.Errorf("repeat_interval não pode ser negativo")
+	}
+	if c.Application.RepeatInterval == 0 {
+		c.Application.RepeatInterval = DefaultRepeatInterval
+	}
+
+	if c.Application.RepeatBackoff != 0 && c.Application.RepeatBackoff < 1 {
+		return fmt.Errorf("repeat_backoff deve ser maior ou igual a 1")
+	}
+	if c.Application.MaxRepeatInterval < 0 {
+		return fmt.Errorf("max_repeat_interval não pode ser negativo")
+	}
+
+	return nil
+}
+
+func (t ThresholdConfig) validate() error {
+	thresholds := map[string]ThresholdLevels{
+		"active_connections":          t.ActiveConnections,
+		"inactive_connections":        t.InactiveConnections,
+		"total_connections":           t.TotalConnections,
+		"replication_lag_seconds":     t.ReplicationLagSeconds,
+		"replication_lag_bytes":       t.ReplicationLagBytes,
+		"long_running_query_seconds":  t.LongRunningQuerySeconds,
+		"blocked_session_seconds":     t.BlockedSessionSeconds,
+		"idle_in_transaction_seconds": t.IdleInTransactionSeconds,
+	}
+	for name, levels := range thresholds {
+		if err := levels.validate(); err != nil {
+			return fmt.Errorf("threshold %s inválido: %w", name, err)
+		}
+	}
+
+	percentages := map[string]ThresholdLevels{
+		"active_connections_pct":   t.ActiveConnectionsPct,
+		"inactive_connections_pct": t.InactiveConnectionsPct,
+		"total_connections_pct":    t.TotalConnectionsPct,
+	}
+	for name, levels := range percentages {
+		if err := levels.validate(); err != nil {
+			return fmt.Errorf("threshold %s inválido: %w", name, err)
+		}
+		if levels.Warning > 100 || levels.Critical > 100 || levels.Clear > 100 {
+			return fmt.Errorf("threshold %s inválido: percentual deve estar entre 0 e 100", name)
+		}
+	}
+	return nil
+}
+
+// merge retorna os thresholds com as métricas definidas em override
+// substituindo as da configuração base.
+func (t ThresholdConfig) merge(override ThresholdConfig) ThresholdConfig {
+	t.ActiveConnections = t.ActiveConnections.merge(override.ActiveConnections)
+	t.InactiveConnections = t.InactiveConnections.merge(override.InactiveConnections)
+	t.TotalConnections = t.TotalConnections.merge(override.TotalConnections)
+	t.ActiveConnectionsPct = t.ActiveConnectionsPct.merge(override.ActiveConnectionsPct)
+	t.InactiveConnectionsPct = t.InactiveConnectionsPct.merge(override.InactiveConnectionsPct)
+	t.TotalConnectionsPct = t.TotalConnectionsPct.merge(override.TotalConnectionsPct)
+	t.ReplicationLagSeconds = t.ReplicationLagSeconds.merge(override.ReplicationLagSeconds)
+	t.ReplicationLagBytes = t.ReplicationLagBytes.merge(override.ReplicationLagBytes)
+	t.LongRunningQuerySeconds = t.LongRunningQuerySeconds.merge(override.LongRunningQuerySeconds)
+	t.BlockedSessionSeconds = t.BlockedSessionSeconds.merge(override.BlockedSessionSeconds)
+	t.IdleInTransactionSeconds = t.IdleInTransactionSeconds.merge(override.IdleInTransactionSeconds)
+	return t
+}
+
+// EffectiveThresholds resolve os thresholds de uma base, aplicando as
+// sobrescritas da própria base sobre o bloco global.
+func (c *Config) EffectiveThresholds(db DatabaseConfig) ThresholdConfig {
+	return c.Thresholds.merge(db.Thresholds)
+}
+
+// AlertTemplates retorna os templates de alerta já carregados e validados
+// pelo Load.
+func (c *Config) AlertTemplates() *templates.Set {
+	return c.alertTemplates
+}
+
+// EffectiveRepeatInterval resolve o intervalo de renotificação de uma base,
+// em segundos.
+func (c *Config) EffectiveRepeatInterval(db DatabaseConfig) int {
+	if db.RepeatInterval > 0 {
+		return db.RepeatInterval
+	}
+	return c.Application.RepeatInterval
+}
+
+func (c *Config) channelNames() map[string]bool {
+	names := map[string]bool{"email": true, "slack": true, "teams": true, "pagerduty": true, "syslog": true}
+	for _, w := range c.Webhooks {
+		names["webhook-"+w.Name] = true
+	}
+	return names
+}
+
+// validateChannels verifica os nomes, timeouts e se as cadeias de fallback
+// não formam ciclos.
+func (c *Config) validateChannels() error {
+	names := c.channelNames()
+
+	for name, ch := range c.Channels {
+		if !names[name] {
+			return fmt.Errorf("canal desconhecido em channels: %s", name)
+		}
+		if ch.Timeout < 0 {
+			return fmt.Errorf("timeout do canal %s não pode ser negativo", name)
+		}
+		if ch.Fallback != "" && !names[ch.Fallback] {
+			return fmt.Errorf("fallback desconhecido para o canal %s: %s", name, ch.Fallback)
+		}
+
+		visited := map[string]bool{name: true}
+		for next := ch.Fallback; next != ""; next = c.Channels[next].Fallback {
+			if visited[next] {
+				return fmt.Errorf("ciclo de fallback a partir do canal %s", name)
+			}
+			visited[next] = true
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateRouting() error {
+	channels := c.channelNames()
+	receivers := make(map[string]bool)
+
+	for i, r := range c.Receivers {
+		if r.Name == "" {
+			return fmt.Errorf("nome do receiver %d não pode estar vazio", i)
+		}
+		if receivers[r.Name] {
+			return fmt.Errorf("receiver duplicado: %s", r.Name)
+		}
+		receivers[r.Name] = true
+
+		for _, ch := range r.Channels {
+			if !channels[ch] {
+				return fmt.Errorf("canal desconhecido no receiver %s: %s", r.Name, ch)
+			}
+		}
+	}
+
+	if c.Route == nil {
+		return nil
+	}
+
+	if len(c.Route.Receivers) == 0 {
+		return fmt.Errorf("a rota raiz precisa de ao menos um receiver")
+	}
+	if m := c.Route.Match; len(m.Databases) > 0 || len(m.Labels) > 0 || len(m.AlertTypes) > 0 || len(m.Severities) > 0 {
+		return fmt.Errorf("a rota raiz casa com todos os alertas e não aceita match; use rotas filhas")
+	}
+
+	var validate func(route RouteConfig, path string) error
+	validate = func(route RouteConfig, path string) error {
+		for _, r := range route.Receivers {
+			if !receivers[r] {
+				return fmt.Errorf("receiver desconhecido em %s: %s", path, r)
+			}
+		}
+		if err := validateSeverities(route.Match.Severities); err != nil {
+			return fmt.Errorf("severidades inválidas em %s: %w", path, err)
+		}
+		for i, child := range route.Routes {
+			if err := validate(child, fmt.Sprintf("%s.routes[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return validate(*c.Route, "route")
+}
+
+// validChannelName garante que o nome pode ser usado como diretório do spool.
+func validChannelName(name string) bool {
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func validateSeverities(severities []string) error {
+	for _, severity := range severities {
+		if severity != "warning" && severity != "critical" {
+			return fmt.Errorf("severidade desconhecida: %s", severity)
+		}
+	}
 	return nil
 }
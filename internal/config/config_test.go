@@ -0,0 +1,62 @@
+package config
+
+import "testing"
+
+func TestMigrateRenamedKeys(t *testing.T) {
+	tests := []struct {
+		name           string
+		yaml           string
+		repeatInterval int
+		wantApp        int
+		wantDatabase   int
+	}{
+		{
+			name:    "alert_frequency vira verificações vezes monitoring_interval",
+			yaml:    "application:\n  alert_frequency: 10\n",
+			wantApp: 600,
+		},
+		{
+			name:    "alert_reset_interval sem alert_frequency",
+			yaml:    "application:\n  alert_reset_interval: 3600\n",
+			wantApp: 3600,
+		},
+		{
+			name:    "alert_frequency tem prioridade sobre alert_reset_interval",
+			yaml:    "application:\n  alert_frequency: 5\n  alert_reset_interval: 3600\n",
+			wantApp: 300,
+		},
+		{
+			name:           "repeat_interval configurado prevalece",
+			yaml:           "application:\n  alert_frequency: 10\n",
+			repeatInterval: 900,
+			wantApp:        900,
+		},
+		{
+			name:         "alert_frequency da base",
+			yaml:         "databases:\n  - name: pg\n    alert_frequency: 3\n",
+			wantDatabase: 180,
+		},
+		{
+			name: "sem chaves antigas",
+			yaml: "application:\n  repeat_interval: 0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{
+				Application: ApplicationConfig{MonitoringInterval: 60, RepeatInterval: tt.repeatInterval},
+				Databases:   []DatabaseConfig{{Name: "pg"}},
+			}
+			if err := c.migrateRenamedKeys([]byte(tt.yaml)); err != nil {
+				t.Fatal(err)
+			}
+			if c.Application.RepeatInterval != tt.wantApp {
+				t.Errorf("repeat_interval = %d, esperado %d", c.Application.RepeatInterval, tt.wantApp)
+			}
+			if c.Databases[0].RepeatInterval != tt.wantDatabase {
+				t.Errorf("repeat_interval da base = %d, esperado %d", c.Databases[0].RepeatInterval, tt.wantDatabase)
+			}
+		})
+	}
+}
@@ -20,15 +20,49 @@ type StatsProvider interface {
 	GetSessionStats(ctx context.Context, db *sql.DB, queryTimeout int) (*SessionStats, error)
 }
 
+// As coletas opcionais abaixo são implementadas apenas pelos providers que as
+// suportam; nos demais tipos de base, os métodos correspondentes da
+// Connection retornam nil, sem erro.
+
+// ReplicationProvider é implementado pelos providers que sabem coletar o
+// estado de replicação do servidor.
+type ReplicationProvider interface {
+	GetReplicationStats(ctx context.Context, db *sql.DB, queryTimeout int) (*ReplicationStats, error)
+}
+
+// LongRunningQueryProvider é implementado pelos providers que sabem listar
+// as consultas em execução há mais de minSeconds segundos.
+type LongRunningQueryProvider interface {
+	GetLongRunningQueries(ctx context.Context, db *sql.DB, queryTimeout int, minSeconds int) ([]LongRunningQuery, error)
+}
+
+// BlockingTreeProvider é implementado pelos providers que sabem montar a
+// árvore de sessões bloqueadas por locks.
+type BlockingTreeProvider interface {
+	GetBlockingTree(ctx context.Context, db *sql.DB, queryTimeout int) (*BlockingTree, error)
+}
+
+// IdleTransactionProvider é implementado pelos providers que sabem listar e
+// encerrar sessões paradas dentro de transações.
+type IdleTransactionProvider interface {
+	GetIdleTransactions(ctx context.Context, db *sql.DB, queryTimeout int) ([]IdleTransaction, error)
+	TerminateSession(ctx context.Context, db *sql.DB, queryTimeout int, session IdleTransaction) error
+}
+
 type SessionStats struct {
-	Active       int
-	Inactive     int
-	Idle         int
-	IdleInTxn    int
-	Waiting      int
-	Total        int
-	DatabaseName string
-	Timestamp    string
+	Active         int
+	Inactive       int
+	Idle           int
+	IdleInTxn      int
+	Waiting        int
+	Total          int
+	MaxConnections int
+	DatabaseName   string
+	Timestamp      string
+	Replication    *ReplicationStats
+	LongQueries    []LongRunningQuery
+
+	OldestIdleInTxnSeconds float64
 }
 
 func NewConnection(cfg config.DatabaseConfig, poolCfg config.PoolConfig) (*Connection, error) {
@@ -107,6 +141,75 @@ func (c *Connection) GetSessionStats(ctx context.Context) (*SessionStats, error)
 	return stats, nil
 }
 
+func (c *Connection) GetReplicationStats(ctx context.Context) (*ReplicationStats, error) {
+	provider, ok := c.stats.(ReplicationProvider)
+	if !ok {
+		return nil, nil
+	}
+
+	stats, err := provider.GetReplicationStats(ctx, c.db, c.config.QueryTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replication stats for %s: %w", c.config.Name, err)
+	}
+
+	return stats, nil
+}
+
+func (c *Connection) GetLongRunningQueries(ctx context.Context, minSeconds int) ([]LongRunningQuery, error) {
+	provider, ok := c.stats.(LongRunningQueryProvider)
+	if !ok {
+		return nil, nil
+	}
+
+	queries, err := provider.GetLongRunningQueries(ctx, c.db, c.config.QueryTimeout, minSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get long running queries for %s: %w", c.config.Name, err)
+	}
+
+	return queries, nil
+}
+
+func (c *Connection) GetBlockingTree(ctx context.Context) (*BlockingTree, error) {
+	provider, ok := c.stats.(BlockingTreeProvider)
+	if !ok {
+		return nil, nil
+	}
+
+	tree, err := provider.GetBlockingTree(ctx, c.db, c.config.QueryTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocking tree for %s: %w", c.config.Name, err)
+	}
+
+	return tree, nil
+}
+
+func (c *Connection) GetIdleTransactions(ctx context.Context) ([]IdleTransaction, error) {
+	provider, ok := c.stats.(IdleTransactionProvider)
+	if !ok {
+		return nil, nil
+	}
+
+	sessions, err := provider.GetIdleTransactions(ctx, c.db, c.config.QueryTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idle transactions for %s: %w", c.config.Name, err)
+	}
+
+	return sessions, nil
+}
+
+func (c *Connection) TerminateSession(ctx context.Context, session IdleTransaction) error {
+	provider, ok := c.stats.(IdleTransactionProvider)
+	if !ok {
+		return fmt.Errorf("session termination not supported for %s", c.config.Type)
+	}
+
+	if err := provider.TerminateSession(ctx, c.db, c.config.QueryTimeout, session); err != nil {
+		return fmt.Errorf("failed to terminate session on %s: %w", c.config.Name, err)
+	}
+
+	return nil
+}
+
 func (c *Connection) IsHealthy(ctx context.Context) error {
 	if c.db == nil {
 		return fmt.Errorf("database connection is nil")
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"slices"
+	"testing"
+)
+
+// testBlockingTree monta uma árvore com as esperas informadas (waiter,
+// blocker); o tempo de espera de cada sessão é o seu ID em segundos.
+func testBlockingTree(ids []int64, waits [][2]int64) *BlockingTree {
+	b := newBlockingTreeBuilder()
+	for _, id := range ids {
+		b.addSession(BlockingSession{ID: id, WaitSeconds: float64(id)})
+	}
+	for _, w := range waits {
+		b.addWait(w[0], w[1])
+	}
+	return b.build()
+}
+
+func rootIDs(tree *BlockingTree) []int64 {
+	var ids []int64
+	for _, root := range tree.Roots {
+		ids = append(ids, root.ID)
+	}
+	return ids
+}
+
+func TestBlockingTree(t *testing.T) {
+	tests := []struct {
+		name        string
+		ids         []int64
+		waits       [][2]int64
+		wantRoots   []int64
+		wantBlocked int
+		wantLongest int64 // 0: nenhuma sessão bloqueada
+		wantHead    int64 // bloqueador no topo da cadeia da sessão mais antiga
+	}{
+		{
+			name:  "sem bloqueios",
+			ids:   []int64{1, 2},
+			waits: nil,
+		},
+		{
+			name:        "cadeia",
+			ids:         []int64{1, 2, 3},
+			waits:       [][2]int64{{3, 2}, {2, 1}},
+			wantRoots:   []int64{1},
+			wantBlocked: 2,
+			wantLongest: 3,
+			wantHead:    1,
+		},
+		{
+			name:        "espera repetida conta uma vez",
+			ids:         []int64{1, 2},
+			waits:       [][2]int64{{2, 1}, {2, 1}},
+			wantRoots:   []int64{1},
+			wantBlocked: 1,
+			wantLongest: 2,
+			wantHead:    1,
+		},
+		{
+			name:        "ciclo sem raiz própria",
+			ids:         []int64{1, 2},
+			waits:       [][2]int64{{1, 2}, {2, 1}},
+			wantRoots:   []int64{1},
+			wantBlocked: 2,
+			wantLongest: 2,
+			wantHead:    2,
+		},
+		{
+			name:        "bloqueador desconhecido é ignorado",
+			ids:         []int64{1},
+			waits:       [][2]int64{{5, 1}},
+			wantBlocked: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := testBlockingTree(tt.ids, tt.waits)
+
+			if got := rootIDs(tree); !slices.Equal(got, tt.wantRoots) {
+				t.Errorf("raízes = %v, esperado %v", got, tt.wantRoots)
+			}
+			if tree.BlockedCount != tt.wantBlocked {
+				t.Errorf("bloqueadas = %d, esperado %d", tree.BlockedCount, tt.wantBlocked)
+			}
+
+			longest := tree.LongestWaiter()
+			if tt.wantLongest == 0 {
+				if longest != nil {
+					t.Errorf("sessão mais antiga = %d, esperado nenhuma", longest.ID)
+				}
+				return
+			}
+			if longest == nil || longest.ID != tt.wantLongest {
+				t.Fatalf("sessão mais antiga = %v, esperado %d", longest, tt.wantLongest)
+			}
+			if head := tree.HeadBlocker(longest.ID); head == nil || head.ID != tt.wantHead {
+				t.Errorf("bloqueador = %v, esperado %d", head, tt.wantHead)
+			}
+		})
+	}
+}
+
+func TestBlockingTreeNodes(t *testing.T) {
+	tree := testBlockingTree([]int64{1, 2, 3, 4}, [][2]int64{{2, 1}, {3, 1}, {4, 2}})
+
+	root := tree.Roots[0]
+	if len(root.Blocking) != 2 || root.Blocking[0].ID != 2 || root.Blocking[1].ID != 3 {
+		t.Fatalf("sessões bloqueadas por 1 = %+v", root.Blocking)
+	}
+	if child := root.Blocking[0]; len(child.Blocking) != 1 || child.Blocking[0].ID != 4 {
+		t.Fatalf("sessões bloqueadas por 2 = %+v", child.Blocking)
+	}
+}
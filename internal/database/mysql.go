@@ -8,11 +8,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"dbMonitor/internal/config"
 	"github.com/go-sql-driver/mysql"
-	_ "github.com/go-sql-driver/mysql"
 )
 
 type MySQLStatsProvider struct{}
@@ -27,7 +28,8 @@ func (m *MySQLStatsProvider) GetSessionStats(ctx context.Context, db *sql.DB, qu
 			COALESCE(SUM(CASE WHEN command = 'Sleep' THEN 1 ELSE 0 END), 0) as idle,
 			COALESCE(SUM(CASE WHEN command != 'Sleep' AND state != '' THEN 1 ELSE 0 END), 0) as active,
 			COALESCE(SUM(CASE WHEN state LIKE '%Waiting%' THEN 1 ELSE 0 END), 0) as waiting,
-			COALESCE(COUNT(*), 0) as total
+			COALESCE(COUNT(*), 0) as total,
+			@@max_connections as max_connections
 		FROM information_schema.processlist 
 		WHERE id != CONNECTION_ID()
 	`
@@ -36,9 +38,9 @@ func (m *MySQLStatsProvider) GetSessionStats(ctx context.Context, db *sql.DB, qu
 	defer cancel()
 
 	var stats SessionStats
-	var idle, active, waiting, total int
+	var idle, active, waiting, total, maxConnections int
 
-	err := db.QueryRowContext(queryCtx, query).Scan(&idle, &active, &waiting, &total)
+	err := db.QueryRowContext(queryCtx, query).Scan(&idle, &active, &waiting, &total, &maxConnections)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query MySQL statistics: %w", err)
 	}
@@ -49,12 +51,229 @@ func (m *MySQLStatsProvider) GetSessionStats(ctx context.Context, db *sql.DB, qu
 	stats.Total = total
 	stats.Inactive = idle
 	stats.IdleInTxn = 0
+	stats.MaxConnections = maxConnections
 
 	return &stats, nil
 }
 
+func (m *MySQLStatsProvider) GetReplicationStats(ctx context.Context, db *sql.DB, queryTimeout int) (*ReplicationStats, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Duration(queryTimeout)*time.Second)
+	defer cancel()
+
+	status, err := queryReplicaStatus(queryCtx, db, "SHOW REPLICA STATUS")
+	if err != nil {
+		// Servidores anteriores ao 8.0.22 só conhecem SHOW SLAVE STATUS
+		status, err = queryReplicaStatus(queryCtx, db, "SHOW SLAVE STATUS")
+		if err != nil {
+			return nil, fmt.Errorf("failed to query MySQL replica status: %w", err)
+		}
+	}
+
+	if status == nil {
+		return &ReplicationStats{Role: ReplicationRolePrimary}, nil
+	}
+
+	// Os nomes das colunas mudaram de Master/Slave para Source/Replica no 8.0.22
+	column := func(names ...string) string {
+		for _, name := range names {
+			if value, ok := status[name]; ok {
+				return value
+			}
+		}
+		return ""
+	}
+
+	stats := &ReplicationStats{
+		Role:             ReplicationRoleReplica,
+		IOThreadRunning:  column("Replica_IO_Running", "Slave_IO_Running"),
+		SQLThreadRunning: column("Replica_SQL_Running", "Slave_SQL_Running"),
+		LastIOError:      column("Last_IO_Error"),
+		LastSQLError:     column("Last_SQL_Error"),
+	}
+
+	if behind := column("Seconds_Behind_Source", "Seconds_Behind_Master"); behind != "" {
+		if seconds, err := strconv.ParseFloat(behind, 64); err == nil {
+			stats.LagSeconds = seconds
+		}
+	}
+
+	retrieved := column("Retrieved_Gtid_Set")
+	executed := column("Executed_Gtid_Set")
+	if retrieved != "" {
+		var missing string
+		err := db.QueryRowContext(queryCtx, "SELECT GTID_SUBTRACT(?, ?)", retrieved, executed).Scan(&missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute GTID gap: %w", err)
+		}
+		stats.GTIDGap = countGTIDSet(missing)
+	}
+
+	return stats, nil
+}
+
+// queryReplicaStatus retorna a primeira linha do status de replicação indexada
+// pelo nome da coluna, ou nil quando o servidor não é réplica.
+func queryReplicaStatus(ctx context.Context, db *sql.DB, query string) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	status := make(map[string]string, len(columns))
+	for i, name := range columns {
+		status[name] = string(values[i])
+	}
+
+	return status, nil
+}
+
+// countGTIDSet conta as transações de um GTID set no formato
+// "uuid:1-5:7,uuid2:1-3".
+func countGTIDSet(set string) int64 {
+	var total int64
+	for _, entry := range strings.Split(set, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		for _, interval := range parts[1:] {
+			bounds := strings.SplitN(interval, "-", 2)
+			start, err := strconv.ParseInt(bounds[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			end := start
+			if len(bounds) == 2 {
+				if end, err = strconv.ParseInt(bounds[1], 10, 64); err != nil {
+					continue
+				}
+			}
+			total += end - start + 1
+		}
+	}
+	return total
+}
+
+func (m *MySQLStatsProvider) GetLongRunningQueries(ctx context.Context, db *sql.DB, queryTimeout int, minSeconds int) ([]LongRunningQuery, error) {
+	query := `
+		SELECT 
+			id,
+			COALESCE(user, ''),
+			COALESCE(host, ''),
+			time,
+			COALESCE(info, '')
+		FROM information_schema.processlist 
+		WHERE id != CONNECTION_ID()
+		AND command NOT IN ('Sleep', 'Daemon', 'Binlog Dump', 'Binlog Dump GTID')
+		AND info IS NOT NULL
+		AND time >= ?
+		ORDER BY time DESC
+	`
+
+	queryCtx, cancel := context.WithTimeout(ctx, time.Duration(queryTimeout)*time.Second)
+	defer cancel()
+
+	rows, err := db.QueryContext(queryCtx, query, minSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query MySQL long running queries: %w", err)
+	}
+	defer rows.Close()
+
+	var queries []LongRunningQuery
+	for rows.Next() {
+		var q LongRunningQuery
+		if err := rows.Scan(&q.ID, &q.User, &q.ClientAddr, &q.DurationSeconds, &q.Query); err != nil {
+			return nil, fmt.Errorf("failed to scan long running query row: %w", err)
+		}
+		q.Query = normalizeQuery(q.Query)
+		queries = append(queries, q)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating long running query rows: %w", err)
+	}
+
+	return queries, nil
+}
+
+func (m *MySQLStatsProvider) GetBlockingTree(ctx context.Context, db *sql.DB, queryTimeout int) (*BlockingTree, error) {
+	// sys.innodb_lock_waits é construída sobre performance_schema.data_lock_waits
+	query := `
+		SELECT 
+			w.waiting_pid,
+			COALESCE(wp.user, ''),
+			COALESCE(wp.host, ''),
+			COALESCE(wp.state, ''),
+			COALESCE(w.wait_age_secs, 0),
+			COALESCE(w.locked_table, ''),
+			COALESCE(w.waiting_query, ''),
+			w.blocking_pid,
+			COALESCE(bp.user, ''),
+			COALESCE(bp.host, ''),
+			COALESCE(bp.state, bp.command, ''),
+			COALESCE(w.blocking_query, '')
+		FROM sys.innodb_lock_waits w
+		LEFT JOIN information_schema.processlist wp ON wp.id = w.waiting_pid
+		LEFT JOIN information_schema.processlist bp ON bp.id = w.blocking_pid
+	`
+
+	queryCtx, cancel := context.WithTimeout(ctx, time.Duration(queryTimeout)*time.Second)
+	defer cancel()
+
+	rows, err := db.QueryContext(queryCtx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query MySQL lock waits: %w", err)
+	}
+	defer rows.Close()
+
+	builder := newBlockingTreeBuilder()
+	type wait struct{ waiter, blocker int64 }
+	var waits []wait
+
+	for rows.Next() {
+		var waiter, blocker BlockingSession
+		if err := rows.Scan(&waiter.ID, &waiter.User, &waiter.ClientAddr, &waiter.State, &waiter.WaitSeconds,
+			&waiter.LockedObject, &waiter.Query, &blocker.ID, &blocker.User, &blocker.ClientAddr,
+			&blocker.State, &blocker.Query); err != nil {
+			return nil, fmt.Errorf("failed to scan MySQL lock wait row: %w", err)
+		}
+		waiter.Query = normalizeQuery(waiter.Query)
+		blocker.Query = normalizeQuery(blocker.Query)
+		builder.addSession(waiter)
+		builder.addSession(blocker)
+		waits = append(waits, wait{waiter.ID, blocker.ID})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating MySQL lock wait rows: %w", err)
+	}
+
+	for _, w := range waits {
+		builder.addWait(w.waiter, w.blocker)
+	}
+
+	return builder.build(), nil
+}
+
 func connectMySQL(cfg config.DatabaseConfig) (*sql.DB, error) {
-	var tlsConfig *mysql.TLSConfig
+	var tlsConfig *tls.Config
 	var err error
 
 	if cfg.CertPath != "" {
@@ -87,8 +306,10 @@ func connectMySQL(cfg config.DatabaseConfig) (*sql.DB, error) {
 	}
 
 	if tlsConfig != nil {
-		mysqlCfg.TLSConfig = "custom"
-		mysqlCfg.TLS = tlsConfig
+		if err := mysql.RegisterTLSConfig(cfg.Name, tlsConfig); err != nil {
+			return nil, fmt.Errorf("failed to register MySQL TLS config: %w", err)
+		}
+		mysqlCfg.TLSConfig = cfg.Name
 	}
 
 	dsn := mysqlCfg.FormatDSN()
@@ -109,7 +330,7 @@ func connectMySQL(cfg config.DatabaseConfig) (*sql.DB, error) {
 	return db, nil
 }
 
-func loadMySQLTLSConfig(certPath string) (*mysql.TLSConfig, error) {
+func loadMySQLTLSConfig(certPath string) (*tls.Config, error) {
 	if err := validateTLSCertFiles(certPath); err != nil {
 		return nil, fmt.Errorf("certificate validation failed: %w", err)
 	}
@@ -132,7 +353,7 @@ func loadMySQLTLSConfig(certPath string) (*mysql.TLSConfig, error) {
 		return nil, fmt.Errorf("failed to append CA cert")
 	}
 
-	return &mysql.TLSConfig{
+	return &tls.Config{
 		Certificates: []tls.Certificate{cert},
 		RootCAs:      rootCertPool,
 	}, nil
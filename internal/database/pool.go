@@ -42,11 +42,12 @@ func (p *Pool) GetConnection(cfg config.DatabaseConfig) (*Connection, error) {
 	if conn, exists := p.connections[cfg.Name]; exists {
 		p.mu.RUnlock()
 
-		if err := conn.IsHealthy(context.Background()); err == nil {
+		err := conn.IsHealthy(context.Background())
+		if err == nil {
 			return conn, nil
 		}
-
 		log.Printf("Connection to %s is unhealthy, recreating: %v", cfg.Name, err)
+
 		p.removeConnection(cfg.Name)
 	} else {
 		p.mu.RUnlock()
@@ -55,6 +56,15 @@ func (p *Pool) GetConnection(cfg config.DatabaseConfig) (*Connection, error) {
 	return p.createConnection(cfg)
 }
 
+// Connection retorna a conexão já existente para a base, sem criar uma nova.
+func (p *Pool) Connection(name string) (*Connection, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	conn, exists := p.connections[name]
+	return conn, exists
+}
+
 func (p *Pool) createConnection(cfg config.DatabaseConfig) (*Connection, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -142,7 +152,7 @@ func (p *Pool) getConnectionStats(ctx context.Context, name string, conn *Connec
 		OpenConnections:  dbStats.OpenConnections,
 		IdleConnections:  dbStats.Idle,
 		InUseConnections: dbStats.InUse,
-		MaxConnections:   dbStats.MaxOpenConns,
+		MaxConnections:   dbStats.MaxOpenConnections,
 		TotalQueries:     int64(dbStats.OpenConnections), // Approximation
 		ConnectionStats:  dbStats,
 		LastHealthCheck:  time.Now(),
@@ -151,7 +161,7 @@ func (p *Pool) getConnectionStats(ctx context.Context, name string, conn *Connec
 
 	if conn.config.Type == "postgresql" {
 		if provider, ok := conn.stats.(*PostgreSQLStatsProvider); ok {
-			if extended, err := provider.GetExtendedStats(ctx, conn.db); err == nil {
+			if extended, err := provider.GetExtendedStats(ctx, conn.db, conn.config.QueryTimeout); err == nil {
 				stats.Extended = extended
 			} else {
 				log.Printf("Failed to get extended stats for %s: %v", name, err)
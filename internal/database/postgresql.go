@@ -24,7 +24,8 @@ func (p *PostgreSQLStatsProvider) GetSessionStats(ctx context.Context, db *sql.D
 			COALESCE(SUM(CASE WHEN state = 'idle' THEN 1 ELSE 0 END), 0) as idle,
 			COALESCE(SUM(CASE WHEN state = 'idle in transaction' THEN 1 ELSE 0 END), 0) as idle_in_txn,
 			COALESCE(SUM(CASE WHEN wait_event IS NOT NULL THEN 1 ELSE 0 END), 0) as waiting,
-			COALESCE(COUNT(*), 0) as total
+			COALESCE(COUNT(*), 0) as total,
+			current_setting('max_connections')::int as max_connections
 		FROM pg_stat_activity 
 		WHERE pid != pg_backend_pid()
 		AND state IS NOT NULL
@@ -34,9 +35,9 @@ func (p *PostgreSQLStatsProvider) GetSessionStats(ctx context.Context, db *sql.D
 	defer cancel()
 
 	var stats SessionStats
-	var active, idle, idleInTxn, waiting, total int
+	var active, idle, idleInTxn, waiting, total, maxConnections int
 
-	err := db.QueryRowContext(ctx, query).Scan(&active, &idle, &idleInTxn, &waiting, &total)
+	err := db.QueryRowContext(ctx, query).Scan(&active, &idle, &idleInTxn, &waiting, &total, &maxConnections)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query PostgreSQL statistics: %w", err)
 	}
@@ -47,6 +48,7 @@ func (p *PostgreSQLStatsProvider) GetSessionStats(ctx context.Context, db *sql.D
 	stats.Waiting = waiting
 	stats.Total = total
 	stats.Inactive = idle + idleInTxn
+	stats.MaxConnections = maxConnections
 
 	return &stats, nil
 }
@@ -144,3 +146,288 @@ func (p *PostgreSQLStatsProvider) GetExtendedStats(ctx context.Context, db *sql.
 
 	return stats, nil
 }
+
+func (p *PostgreSQLStatsProvider) GetReplicationStats(ctx context.Context, db *sql.DB, queryTimeout int) (*ReplicationStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(queryTimeout)*time.Second)
+	defer cancel()
+
+	var inRecovery bool
+	if err := db.QueryRowContext(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery); err != nil {
+		return nil, fmt.Errorf("failed to check recovery state: %w", err)
+	}
+
+	if inRecovery {
+		return p.getStandbyReplicationStats(ctx, db)
+	}
+
+	return p.getPrimaryReplicationStats(ctx, db)
+}
+
+func (p *PostgreSQLStatsProvider) getPrimaryReplicationStats(ctx context.Context, db *sql.DB) (*ReplicationStats, error) {
+	query := `
+		SELECT 
+			COALESCE(application_name, ''),
+			COALESCE(client_addr::text, ''),
+			COALESCE(state, ''),
+			COALESCE(EXTRACT(EPOCH FROM write_lag), 0)::float8,
+			COALESCE(EXTRACT(EPOCH FROM flush_lag), 0)::float8,
+			COALESCE(EXTRACT(EPOCH FROM replay_lag), 0)::float8,
+			COALESCE(pg_wal_lsn_diff(sent_lsn, replay_lsn), 0)::bigint
+		FROM pg_stat_replication
+	`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pg_stat_replication: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &ReplicationStats{Role: ReplicationRolePrimary}
+	for rows.Next() {
+		var replica ReplicaStatus
+		if err := rows.Scan(&replica.ApplicationName, &replica.ClientAddr, &replica.State,
+			&replica.WriteLagSeconds, &replica.FlushLagSeconds, &replica.ReplayLagSeconds, &replica.ReplayLagBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan replication row: %w", err)
+		}
+
+		if replica.ReplayLagSeconds > stats.LagSeconds {
+			stats.LagSeconds = replica.ReplayLagSeconds
+		}
+		if replica.ReplayLagBytes > stats.LagBytes {
+			stats.LagBytes = replica.ReplayLagBytes
+		}
+
+		stats.Replicas = append(stats.Replicas, replica)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating replication rows: %w", err)
+	}
+
+	return stats, nil
+}
+
+// getStandbyReplicationStats mede o atraso do próprio standby. Com todo o WAL
+// recebido já aplicado, o atraso é zero: sem escrita no primário, o horário da
+// última transação aplicada envelhece sem que haja atraso real.
+func (p *PostgreSQLStatsProvider) getStandbyReplicationStats(ctx context.Context, db *sql.DB) (*ReplicationStats, error) {
+	query := `
+		SELECT 
+			CASE
+				WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+				ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+			END::float8,
+			COALESCE(pg_wal_lsn_diff(pg_last_wal_receive_lsn(), pg_last_wal_replay_lsn()), 0)::bigint,
+			pg_last_xact_replay_timestamp()
+	`
+
+	var lastReplay sql.NullTime
+	stats := &ReplicationStats{Role: ReplicationRoleStandby}
+
+	err := db.QueryRowContext(ctx, query).Scan(&stats.LagSeconds, &stats.LagBytes, &lastReplay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query standby replay state: %w", err)
+	}
+
+	if lastReplay.Valid {
+		stats.LastReplayTimestamp = &lastReplay.Time
+	}
+
+	return stats, nil
+}
+
+func (p *PostgreSQLStatsProvider) GetLongRunningQueries(ctx context.Context, db *sql.DB, queryTimeout int, minSeconds int) ([]LongRunningQuery, error) {
+	query := `
+		SELECT 
+			pid,
+			COALESCE(usename, ''),
+			COALESCE(client_addr::text, ''),
+			COALESCE(application_name, ''),
+			EXTRACT(EPOCH FROM now() - query_start)::float8,
+			COALESCE(query, '')
+		FROM pg_stat_activity 
+		WHERE pid != pg_backend_pid()
+		AND state = 'active'
+		AND backend_type = 'client backend'
+		AND query_start < now() - make_interval(secs => $1)
+		ORDER BY query_start
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(queryTimeout)*time.Second)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query, minSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query long running queries: %w", err)
+	}
+	defer rows.Close()
+
+	var queries []LongRunningQuery
+	for rows.Next() {
+		var q LongRunningQuery
+		if err := rows.Scan(&q.ID, &q.User, &q.ClientAddr, &q.ApplicationName, &q.DurationSeconds, &q.Query); err != nil {
+			return nil, fmt.Errorf("failed to scan long running query row: %w", err)
+		}
+		q.Query = normalizeQuery(q.Query)
+		queries = append(queries, q)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating long running query rows: %w", err)
+	}
+
+	return queries, nil
+}
+
+// GetBlockingTree mede a espera pelo waitstart do lock (PostgreSQL 14+, lido
+// via to_jsonb para a consulta também rodar em versões anteriores) e, sem
+// ele, pelo state_change das sessões aguardando lock.
+func (p *PostgreSQLStatsProvider) GetBlockingTree(ctx context.Context, db *sql.DB, queryTimeout int) (*BlockingTree, error) {
+	query := `
+		WITH blocked AS (
+			SELECT pid, pg_blocking_pids(pid) AS blockers
+			FROM pg_stat_activity
+			WHERE cardinality(pg_blocking_pids(pid)) > 0
+		)
+		SELECT 
+			a.pid,
+			COALESCE(a.usename, ''),
+			COALESCE(a.client_addr::text, ''),
+			COALESCE(a.application_name, ''),
+			COALESCE(a.state, ''),
+			CASE WHEN b.pid IS NOT NULL
+				THEN COALESCE(EXTRACT(EPOCH FROM now() - COALESCE(
+					w.waitstart,
+					CASE WHEN a.wait_event_type = 'Lock' THEN a.state_change END
+				)), 0)::float8
+				ELSE 0::float8
+			END,
+			COALESCE(w.locked_object, ''),
+			COALESCE(a.query, ''),
+			COALESCE(b.blockers, '{}')
+		FROM pg_stat_activity a
+		LEFT JOIN blocked b ON b.pid = a.pid
+		LEFT JOIN LATERAL (
+			SELECT
+				l.locktype || COALESCE(' ' || l.relation::regclass::text, '') AS locked_object,
+				(to_jsonb(l) ->> 'waitstart')::timestamptz AS waitstart
+			FROM pg_locks l
+			WHERE l.pid = a.pid AND NOT l.granted
+			LIMIT 1
+		) w ON true
+		WHERE b.pid IS NOT NULL
+		OR a.pid IN (SELECT unnest(blockers) FROM blocked)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(queryTimeout)*time.Second)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocking sessions: %w", err)
+	}
+	defer rows.Close()
+
+	builder := newBlockingTreeBuilder()
+	waits := make(map[int64][]int64)
+
+	for rows.Next() {
+		var session BlockingSession
+		var blockers []int64
+		if err := rows.Scan(&session.ID, &session.User, &session.ClientAddr, &session.ApplicationName,
+			&session.State, &session.WaitSeconds, &session.LockedObject, &session.Query, pq.Array(&blockers)); err != nil {
+			return nil, fmt.Errorf("failed to scan blocking session row: %w", err)
+		}
+		session.Query = normalizeQuery(session.Query)
+		builder.addSession(session)
+		waits[session.ID] = blockers
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating blocking session rows: %w", err)
+	}
+
+	for waiterID, blockers := range waits {
+		for _, blockerID := range blockers {
+			builder.addWait(waiterID, blockerID)
+		}
+	}
+
+	return builder.build(), nil
+}
+
+func (p *PostgreSQLStatsProvider) GetIdleTransactions(ctx context.Context, db *sql.DB, queryTimeout int) ([]IdleTransaction, error) {
+	query := `
+		SELECT 
+			pid,
+			COALESCE(usename, ''),
+			COALESCE(client_addr::text, ''),
+			COALESCE(application_name, ''),
+			xact_start,
+			state_change,
+			EXTRACT(EPOCH FROM now() - state_change)::float8,
+			COALESCE(query, '')
+		FROM pg_stat_activity 
+		WHERE pid != pg_backend_pid()
+		AND state IN ('idle in transaction', 'idle in transaction (aborted)')
+		AND xact_start IS NOT NULL
+		AND state_change IS NOT NULL
+		ORDER BY state_change
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(queryTimeout)*time.Second)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query idle in transaction sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []IdleTransaction
+	for rows.Next() {
+		var s IdleTransaction
+		if err := rows.Scan(&s.ID, &s.User, &s.ClientAddr, &s.ApplicationName, &s.XactStart, &s.StateChange, &s.IdleSeconds, &s.Query); err != nil {
+			return nil, fmt.Errorf("failed to scan idle in transaction row: %w", err)
+		}
+		s.Query = normalizeQuery(s.Query)
+		sessions = append(sessions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating idle in transaction rows: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// TerminateSession confere e encerra a sessão na mesma instrução: se ela
+// saiu do idle in transaction ou já está em outra transação (ou o pid foi
+// reaproveitado), nada é encerrado.
+func (p *PostgreSQLStatsProvider) TerminateSession(ctx context.Context, db *sql.DB, queryTimeout int, session IdleTransaction) error {
+	query := `
+		SELECT pg_terminate_backend(pid)
+		FROM pg_stat_activity
+		WHERE pid = $1
+		AND state LIKE 'idle in transaction%'
+		AND xact_start = $2
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(queryTimeout)*time.Second)
+	defer cancel()
+
+	var terminated bool
+	err := db.QueryRowContext(ctx, query, session.ID, session.XactStart).Scan(&terminated)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("backend %d is no longer idle in the same transaction, not terminated", session.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to terminate backend %d: %w", session.ID, err)
+	}
+
+	if !terminated {
+		return fmt.Errorf("backend %d was not terminated", session.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+const maxQueryTextLength = 300
+
+// LongRunningQuery descreve uma sessão executando a mesma consulta há mais
+// tempo que o limite configurado. No MySQL, ApplicationName fica vazio.
+type LongRunningQuery struct {
+	ID              int64   `json:"id"`
+	User            string  `json:"user"`
+	ClientAddr      string  `json:"client_addr"`
+	ApplicationName string  `json:"application_name,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Query           string  `json:"query"`
+}
+
+// IdleTransaction descreve uma sessão parada dentro de uma transação aberta.
+// IdleSeconds é medido a partir do state_change da sessão; XactStart identifica
+// a transação, para que o encerramento não atinja outra transação da sessão.
+type IdleTransaction struct {
+	ID              int64     `json:"id"`
+	User            string    `json:"user"`
+	ClientAddr      string    `json:"client_addr"`
+	ApplicationName string    `json:"application_name"`
+	XactStart       time.Time `json:"xact_start"`
+	StateChange     time.Time `json:"state_change"`
+	IdleSeconds     float64   `json:"idle_seconds"`
+	Query           string    `json:"query"`
+}
+
+var (
+	queryStringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	queryNumericLiteral = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	queryWhitespace     = regexp.MustCompile(`\s+`)
+)
+
+// normalizeQuery substitui literais por "?", colapsa espaços e trunca o texto,
+// evitando que valores sensíveis e consultas enormes cheguem aos alertas.
+func normalizeQuery(query string) string {
+	query = queryStringLiteral.ReplaceAllString(query, "?")
+	query = queryNumericLiteral.ReplaceAllString(query, "?")
+	query = strings.TrimSpace(queryWhitespace.ReplaceAllString(query, " "))
+
+	if runes := []rune(query); len(runes) > maxQueryTextLength {
+		query = string(runes[:maxQueryTextLength]) + "..."
+	}
+
+	return query
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "literais de texto e número", query: "SELECT * FROM pedidos WHERE cliente = 'ana' AND total > 10.5", want: "SELECT * FROM pedidos WHERE cliente = ? AND total > ?"},
+		{name: "aspas escapadas", query: "UPDATE t SET nome = 'd''avila' WHERE id = 7", want: "UPDATE t SET nome = ? WHERE id = ?"},
+		{name: "espaços colapsados", query: "  SELECT\n\t1\n", want: "SELECT ?"},
+		{name: "números em identificadores ficam", query: "SELECT col1 FROM t2", want: "SELECT col1 FROM t2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeQuery(tt.query); got != tt.want {
+				t.Fatalf("normalizeQuery(%q) = %q, esperado %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeQueryTruncates(t *testing.T) {
+	got := normalizeQuery("SELECT " + strings.Repeat("çx", maxQueryTextLength))
+	if runes := []rune(got); len(runes) != maxQueryTextLength+3 || !strings.HasSuffix(got, "...") {
+		t.Fatalf("consulta truncada com %d runes: %q", len(runes), got)
+	}
+}
@@ -0,0 +1,46 @@
+package database
+
+import "time"
+
+const (
+	ReplicationRolePrimary = "primary"
+	ReplicationRoleStandby = "standby"
+	ReplicationRoleReplica = "replica"
+)
+
+// ReplicationStats resume o estado de replicação de um servidor. LagSeconds
+// e LagBytes trazem o pior caso: a réplica mais atrasada, no primário, ou o
+// atraso do próprio servidor, quando ele é standby (PostgreSQL) ou réplica
+// (MySQL).
+type ReplicationStats struct {
+	Role                string          `json:"role"`
+	LagSeconds          float64         `json:"lag_seconds"`
+	LagBytes            int64           `json:"lag_bytes"`
+	LastReplayTimestamp *time.Time      `json:"last_replay_timestamp,omitempty"`
+	Replicas            []ReplicaStatus `json:"replicas,omitempty"`
+
+	// Estado das threads de replicação do MySQL ("Yes", "No", "Connecting").
+	IOThreadRunning  string `json:"io_thread_running,omitempty"`
+	SQLThreadRunning string `json:"sql_thread_running,omitempty"`
+	LastIOError      string `json:"last_io_error,omitempty"`
+	LastSQLError     string `json:"last_sql_error,omitempty"`
+	GTIDGap          int64  `json:"gtid_gap"`
+}
+
+// IsStopped indica se alguma thread de replicação do MySQL não está rodando.
+func (r *ReplicationStats) IsStopped() bool {
+	if r.Role != ReplicationRoleReplica {
+		return false
+	}
+	return r.IOThreadRunning != "Yes" || r.SQLThreadRunning != "Yes"
+}
+
+type ReplicaStatus struct {
+	ApplicationName  string  `json:"application_name"`
+	ClientAddr       string  `json:"client_addr"`
+	State            string  `json:"state"`
+	WriteLagSeconds  float64 `json:"write_lag_seconds"`
+	FlushLagSeconds  float64 `json:"flush_lag_seconds"`
+	ReplayLagSeconds float64 `json:"replay_lag_seconds"`
+	ReplayLagBytes   int64   `json:"replay_lag_bytes"`
+}
@@ -0,0 +1,46 @@
+package database
+
+import "testing"
+
+func TestCountGTIDSet(t *testing.T) {
+	tests := []struct {
+		name string
+		set  string
+		want int64
+	}{
+		{name: "vazio", set: "", want: 0},
+		{name: "intervalo", set: "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5", want: 5},
+		{name: "intervalo e transação avulsa", set: "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5:7", want: 6},
+		{name: "vários servidores", set: "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5:7,\n4f22fb58-71ca-11e1-9e33-c80aa9429562:1-3", want: 9},
+		{name: "intervalo inválido é ignorado", set: "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-x:10-11", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countGTIDSet(tt.set); got != tt.want {
+				t.Fatalf("countGTIDSet(%q) = %d, esperado %d", tt.set, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplicationIsStopped(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats ReplicationStats
+		want  bool
+	}{
+		{name: "réplica rodando", stats: ReplicationStats{Role: ReplicationRoleReplica, IOThreadRunning: "Yes", SQLThreadRunning: "Yes"}},
+		{name: "thread de IO conectando", stats: ReplicationStats{Role: ReplicationRoleReplica, IOThreadRunning: "Connecting", SQLThreadRunning: "Yes"}, want: true},
+		{name: "thread SQL parada", stats: ReplicationStats{Role: ReplicationRoleReplica, IOThreadRunning: "Yes", SQLThreadRunning: "No"}, want: true},
+		{name: "primário não tem threads", stats: ReplicationStats{Role: ReplicationRolePrimary}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.IsStopped(); got != tt.want {
+				t.Fatalf("IsStopped() = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"dbMonitor/internal/notifier"
+)
+
+type AlertStatus struct {
+	ID           string              `json:"id"`
+	DatabaseName string              `json:"database_name"`
+	AlertType    string              `json:"alert_type"`
+	Severity     notifier.Severity   `json:"severity"`
+	State        notifier.AlertState `json:"state"`
+	Message      string              `json:"message"`
+	Value        int                 `json:"value"`
+	Threshold    int                 `json:"threshold"`
+	StartedAt    time.Time           `json:"started_at"`
+	LastSeenAt   time.Time           `json:"last_seen_at"`
+	ResolvedAt   *time.Time          `json:"resolved_at,omitempty"`
+
+	// Occurrences conta as verificações em que a condição foi observada;
+	// Notifications, quantas notificações foram enviadas.
+	Occurrences        int        `json:"occurrences"`
+	Notifications      int        `json:"notifications"`
+	LastNotifiedAt     *time.Time `json:"last_notified_at,omitempty"`
+	NextNotificationAt *time.Time `json:"next_notification_at,omitempty"`
+
+	// NotifiedSeverities são as severidades já entregues, usadas para que a
+	// resolução chegue aos canais de todas elas.
+	NotifiedSeverities []notifier.Severity `json:"notified_severities,omitempty"`
+
+	// SilencedBy é o silence que suprimiu a última notificação do alerta.
+	SilencedBy              string `json:"silenced_by,omitempty"`
+	SuppressedNotifications int    `json:"suppressed_notifications,omitempty"`
+
+	Ack *AlertAck `json:"ack,omitempty"`
+}
+
+// AlertAck interrompe as repetições do alerta até ele ser resolvido ou até
+// ExpiresAt, quando informado.
+type AlertAck struct {
+	Author    string     `json:"author"`
+	Comment   string     `json:"comment"`
+	AckedAt   time.Time  `json:"acked_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (a *AlertAck) isActive(now time.Time) bool {
+	return a != nil && (a.ExpiresAt == nil || now.Before(*a.ExpiresAt))
+}
+
+var (
+	ErrAlertNotFound = errors.New("alert not found")
+	ErrAlertResolved = errors.New("alert already resolved")
+)
+
+func alertKey(databaseName, alertType string) string {
+	return fmt.Sprintf("%s_%s", databaseName, alertType)
+}
+
+// raiseAlert registra uma ocorrência da condição de alerta. Um alerta novo
+// começa como pending e passa a firing quando a primeira notificação é
+// entregue. Enquanto continuar firing, é renotificado a cada repeat_interval
+// (com o backoff configurado), exceto com ack ativo. A escalada de warning
+// para critical é notificada na hora e reinicia a contagem; o rebaixamento
+// também é notificado na hora, para que os canais de warning saibam que o
+// alerta continua ativo.
+func (dm *DatabaseMonitor) raiseAlert(alert notifier.Alert) {
+	now := time.Now()
+
+	dm.mu.Lock()
+	status := dm.observeAlert(alert)
+	switch {
+	case status.Severity == notifier.SeverityWarning && alert.Severity == notifier.SeverityCritical:
+		// A escalada é notificada mesmo com ack, que deixa de valer.
+		status.LastNotifiedAt = nil
+		status.Notifications = 0
+		status.Ack = nil
+	case status.Severity == notifier.SeverityCritical && alert.Severity == notifier.SeverityWarning:
+		status.LastNotifiedAt = nil
+	}
+	dm.updateSeverity(status, alert.Severity)
+
+	if status.Ack != nil && !status.Ack.isActive(now) {
+		status.Ack = nil
+	}
+	acked := status.Ack != nil && status.State == notifier.AlertStateFiring
+
+	notify := !acked && dm.notificationDue(status, now)
+	startedAt := status.StartedAt
+	dm.mu.Unlock()
+
+	if !notify {
+		return
+	}
+
+	alert.StartedAt = startedAt
+	if !dm.sendAlert(alert) {
+		// Suprimido ou não entregue: a notificação continua devida e é
+		// tentada de novo na próxima verificação (ex.: ao fim do silence).
+		return
+	}
+
+	dm.mu.Lock()
+	status.LastNotifiedAt = &now
+	status.Notifications++
+	if !slices.Contains(status.NotifiedSeverities, alert.Severity) {
+		status.NotifiedSeverities = append(status.NotifiedSeverities, alert.Severity)
+	}
+	if status.State == notifier.AlertStatePending {
+		status.State = notifier.AlertStateFiring
+	}
+	dm.mu.Unlock()
+}
+
+// holdAlert registra a violação de um threshold que ainda não se sustentou
+// pelo for/for_checks configurado: o alerta fica pending, sem notificar.
+func (dm *DatabaseMonitor) holdAlert(alert notifier.Alert) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	status := dm.observeAlert(alert)
+	dm.updateSeverity(status, alert.Severity)
+}
+
+// updateSeverity aplica a severidade da ocorrência atual e registra a mudança
+// de severidade de um alerta já disparado. Deve ser chamado com dm.mu travado.
+func (dm *DatabaseMonitor) updateSeverity(status *AlertStatus, severity notifier.Severity) {
+	if status.State == notifier.AlertStateFiring && status.Severity != severity {
+		log.Printf("Alert %s changed severity: %s -> %s", status.ID, status.Severity, severity)
+	}
+	status.Severity = severity
+}
+
+// observeAlert cria ou atualiza o estado do alerta com a ocorrência atual,
+// exceto a severidade. Deve ser chamado com dm.mu travado.
+func (dm *DatabaseMonitor) observeAlert(alert notifier.Alert) *AlertStatus {
+	key := alertKey(alert.DatabaseName, alert.AlertType)
+
+	status, exists := dm.alertStates[key]
+	if !exists || status.State == notifier.AlertStateResolved {
+		status = &AlertStatus{
+			ID:           key,
+			DatabaseName: alert.DatabaseName,
+			AlertType:    alert.AlertType,
+			State:        notifier.AlertStatePending,
+			StartedAt:    alert.Timestamp,
+		}
+		dm.alertStates[key] = status
+	}
+	status.Message = alert.Message
+	status.Value = alert.Value
+	status.Threshold = alert.Threshold
+	status.LastSeenAt = alert.Timestamp
+	status.Occurrences++
+
+	return status
+}
+
+// repeatInterval é o intervalo até a próxima notificação de um alerta que já
+// foi notificado notifications vezes: repeat_interval multiplicado por
+// repeat_backoff a cada repetição, limitado por max_repeat_interval.
+func (dm *DatabaseMonitor) repeatInterval(databaseName string, notifications int) time.Duration {
+	app := dm.config.Application
+
+	interval := time.Duration(app.RepeatInterval) * time.Second
+	if db, ok := dm.databaseConfig(databaseName); ok {
+		interval = time.Duration(dm.config.EffectiveRepeatInterval(db)) * time.Second
+	}
+
+	maxInterval := time.Duration(app.MaxRepeatInterval) * time.Second
+	if maxInterval <= 0 {
+		// Sem limite configurado, evita overflow após muitas repetições.
+		maxInterval = 7 * 24 * time.Hour
+	}
+
+	if app.RepeatBackoff > 1 {
+		for i := 1; i < notifications && interval < maxInterval; i++ {
+			interval = time.Duration(float64(interval) * app.RepeatBackoff)
+		}
+	}
+
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+
+	return interval
+}
+
+// notificationDue deve ser chamado com dm.mu travado.
+func (dm *DatabaseMonitor) notificationDue(status *AlertStatus, now time.Time) bool {
+	if status.LastNotifiedAt == nil {
+		return true
+	}
+	return now.Sub(*status.LastNotifiedAt) >= dm.repeatInterval(status.DatabaseName, status.Notifications)
+}
+
+// clearAlert marca o alerta como resolvido quando a condição deixa de
+// ocorrer e envia a notificação de recuperação se ele chegou a disparar.
+func (dm *DatabaseMonitor) clearAlert(databaseName, alertType string) {
+	key := alertKey(databaseName, alertType)
+
+	dm.mu.Lock()
+	delete(dm.breaches, key)
+	status, exists := dm.alertStates[key]
+	if !exists || status.State == notifier.AlertStateResolved {
+		dm.mu.Unlock()
+		return
+	}
+
+	if status.State == notifier.AlertStatePending {
+		delete(dm.alertStates, key)
+		dm.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	status.State = notifier.AlertStateResolved
+	status.ResolvedAt = &now
+	resolved := *status
+	dm.mu.Unlock()
+
+	dm.sendResolved(resolved)
+}
+
+func (dm *DatabaseMonitor) sendResolved(status AlertStatus) {
+	dm.sendAlert(notifier.Alert{
+		DatabaseName: status.DatabaseName,
+		AlertType:    status.AlertType,
+		Severity:     status.Severity,
+		State:        notifier.AlertStateResolved,
+		Message:      status.Message,
+		Value:        status.Value,
+		Threshold:    status.Threshold,
+		StartedAt:    status.StartedAt,
+		Timestamp:    *status.ResolvedAt,
+
+		NotifiedSeverities: status.NotifiedSeverities,
+	})
+}
+
+// AckAlert registra o ack de um alerta ativo. As repetições param até a
+// resolução ou até expiresAt, quando não for nil.
+func (dm *DatabaseMonitor) AckAlert(id, author, comment string, expiresAt *time.Time) (AlertStatus, error) {
+	if author == "" {
+		return AlertStatus{}, errors.New("author is required")
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return AlertStatus{}, errors.New("expires_at must be in the future")
+	}
+
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	status, exists := dm.alertStates[id]
+	if !exists {
+		return AlertStatus{}, ErrAlertNotFound
+	}
+	if status.State == notifier.AlertStateResolved {
+		return AlertStatus{}, ErrAlertResolved
+	}
+
+	status.Ack = &AlertAck{
+		Author:    author,
+		Comment:   comment,
+		AckedAt:   now,
+		ExpiresAt: expiresAt,
+	}
+
+	log.Printf("Alert %s acknowledged by %s: %s", id, author, comment)
+	return *status, nil
+}
+
+func (dm *DatabaseMonitor) GetAlerts() []AlertStatus {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	now := time.Now()
+	alerts := make([]AlertStatus, 0, len(dm.alertStates))
+	for _, status := range dm.alertStates {
+		alert := *status
+		if !alert.Ack.isActive(now) {
+			alert.Ack = nil
+		}
+		if alert.State == notifier.AlertStateFiring && alert.LastNotifiedAt != nil && alert.Ack == nil {
+			next := alert.LastNotifiedAt.Add(dm.repeatInterval(alert.DatabaseName, alert.Notifications))
+			alert.NextNotificationAt = &next
+		}
+		alerts = append(alerts, alert)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].DatabaseName != alerts[j].DatabaseName {
+			return alerts[i].DatabaseName < alerts[j].DatabaseName
+		}
+		return alerts[i].AlertType < alerts[j].AlertType
+	})
+
+	return alerts
+}
+
+// GetAlertCounts retorna as ocorrências de cada alerta ativo, pela chave
+// "<database>_<alertType>".
+func (dm *DatabaseMonitor) GetAlertCounts() map[string]int {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for key, status := range dm.alertStates {
+		if status.State != notifier.AlertStateResolved {
+			counts[key] = status.Occurrences
+		}
+	}
+	return counts
+}
+
+// ResetAlerts descarta o estado de todos os alertas e as violações em
+// andamento. Alertas que continuarem ativos disparam de novo na próxima
+// verificação como alertas novos; os que normalizarem até lá não geram
+// notificação de resolução.
+func (dm *DatabaseMonitor) ResetAlerts() {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	dm.alertStates = make(map[string]*AlertStatus)
+	dm.breaches = make(map[string]*breachState)
+	log.Println("Alert states reset")
+}
@@ -0,0 +1,320 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"dbMonitor/internal/config"
+	"dbMonitor/internal/database"
+	"dbMonitor/internal/notifier"
+)
+
+// testNotifier registra os alertas entregues e falha enquanto down for true.
+type testNotifier struct {
+	down bool
+	sent []notifier.Alert
+}
+
+func (n *testNotifier) SendAlert(ctx context.Context, alert notifier.Alert) error {
+	if n.down {
+		return errors.New("canal indisponível")
+	}
+	n.sent = append(n.sent, alert)
+	return nil
+}
+
+func newTestMonitor(n notifier.Notifier) *DatabaseMonitor {
+	return &DatabaseMonitor{
+		config:      &config.Config{Application: config.ApplicationConfig{RepeatInterval: 3600}},
+		notifier:    n,
+		lastStats:   make(map[string]*database.SessionStats),
+		alertStates: make(map[string]*AlertStatus),
+		history:     make(map[string][]database.SessionStats),
+		silences:    make(map[string]*Silence),
+		breaches:    make(map[string]*breachState),
+	}
+}
+
+const testAlertType = "HIGH_ACTIVE_CONNECTIONS"
+
+func (dm *DatabaseMonitor) testStatus() (AlertStatus, bool) {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	status, ok := dm.alertStates[alertKey("pg", testAlertType)]
+	if !ok {
+		return AlertStatus{}, false
+	}
+	return *status, true
+}
+
+func TestAlertStateMachine(t *testing.T) {
+	type step struct {
+		value        int
+		wantState    notifier.AlertState // vazio: sem estado para o alerta
+		wantSeverity notifier.Severity
+		wantSent     int
+	}
+
+	levels := config.ThresholdLevels{Warning: 60, Critical: 80}
+	withClear := levels
+	withClear.Clear = 50
+	withForChecks := levels
+	withForChecks.ForChecks = 3
+	withFor := levels
+	withFor.For = 3600
+
+	tests := []struct {
+		name   string
+		levels config.ThresholdLevels
+		steps  []step
+	}{
+		{
+			name:   "dispara, não repete antes do repeat_interval e resolve",
+			levels: levels,
+			steps: []step{
+				{value: 70, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityWarning, wantSent: 1},
+				{value: 75, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityWarning, wantSent: 1},
+				{value: 40, wantState: notifier.AlertStateResolved, wantSeverity: notifier.SeverityWarning, wantSent: 2},
+				{value: 40, wantState: notifier.AlertStateResolved, wantSeverity: notifier.SeverityWarning, wantSent: 2},
+			},
+		},
+		{
+			name:   "for_checks segura o alerta como pending",
+			levels: withForChecks,
+			steps: []step{
+				{value: 70, wantState: notifier.AlertStatePending, wantSeverity: notifier.SeverityWarning},
+				{value: 90, wantState: notifier.AlertStatePending, wantSeverity: notifier.SeverityCritical},
+				{value: 90, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityCritical, wantSent: 1},
+			},
+		},
+		{
+			name:   "pending que normaliza some sem notificar e reinicia a contagem",
+			levels: withForChecks,
+			steps: []step{
+				{value: 70, wantState: notifier.AlertStatePending, wantSeverity: notifier.SeverityWarning},
+				{value: 70, wantState: notifier.AlertStatePending, wantSeverity: notifier.SeverityWarning},
+				{value: 40},
+				{value: 70, wantState: notifier.AlertStatePending, wantSeverity: notifier.SeverityWarning},
+			},
+		},
+		{
+			name:   "for ainda não cumprido",
+			levels: withFor,
+			steps: []step{
+				{value: 90, wantState: notifier.AlertStatePending, wantSeverity: notifier.SeverityCritical},
+				{value: 90, wantState: notifier.AlertStatePending, wantSeverity: notifier.SeverityCritical},
+			},
+		},
+		{
+			name:   "histerese mantém o alerta entre clear e o disparo",
+			levels: withClear,
+			steps: []step{
+				{value: 70, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityWarning, wantSent: 1},
+				{value: 55, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityWarning, wantSent: 1},
+				{value: 50, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityWarning, wantSent: 1},
+				{value: 49, wantState: notifier.AlertStateResolved, wantSeverity: notifier.SeverityWarning, wantSent: 2},
+			},
+		},
+		{
+			name:   "sem histerese resolve ao voltar abaixo do warning",
+			levels: levels,
+			steps: []step{
+				{value: 70, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityWarning, wantSent: 1},
+				{value: 55, wantState: notifier.AlertStateResolved, wantSeverity: notifier.SeverityWarning, wantSent: 2},
+			},
+		},
+		{
+			name:   "escalada para critical notifica na hora",
+			levels: levels,
+			steps: []step{
+				{value: 70, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityWarning, wantSent: 1},
+				{value: 90, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityCritical, wantSent: 2},
+				{value: 90, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityCritical, wantSent: 2},
+			},
+		},
+		{
+			name:   "rebaixamento para warning notifica na hora",
+			levels: levels,
+			steps: []step{
+				{value: 90, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityCritical, wantSent: 1},
+				{value: 70, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityWarning, wantSent: 2},
+				{value: 70, wantState: notifier.AlertStateFiring, wantSeverity: notifier.SeverityWarning, wantSent: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &testNotifier{}
+			dm := newTestMonitor(n)
+
+			for i, s := range tt.steps {
+				dm.evaluateThreshold("pg", testAlertType, "High number of active connections detected", s.value, tt.levels)
+
+				status, ok := dm.testStatus()
+				if s.wantState == "" {
+					if ok {
+						t.Fatalf("passo %d (%d): estado %s, esperado nenhum", i, s.value, status.State)
+					}
+				} else if !ok || status.State != s.wantState || status.Severity != s.wantSeverity {
+					t.Fatalf("passo %d (%d): estado %s/%s, esperado %s/%s", i, s.value, status.State, status.Severity, s.wantState, s.wantSeverity)
+				}
+				if len(n.sent) != s.wantSent {
+					t.Fatalf("passo %d (%d): %d notificações, esperado %d", i, s.value, len(n.sent), s.wantSent)
+				}
+			}
+		})
+	}
+}
+
+func TestResolvedAlertCarriesNotifiedSeverities(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		want   []notifier.Severity
+	}{
+		{name: "só warning", values: []int{70}, want: []notifier.Severity{notifier.SeverityWarning}},
+		{name: "rebaixado de critical", values: []int{90, 70}, want: []notifier.Severity{notifier.SeverityCritical, notifier.SeverityWarning}},
+		{name: "escalado para critical", values: []int{70, 90}, want: []notifier.Severity{notifier.SeverityWarning, notifier.SeverityCritical}},
+	}
+
+	levels := config.ThresholdLevels{Warning: 60, Critical: 80}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &testNotifier{}
+			dm := newTestMonitor(n)
+
+			for _, value := range append(tt.values, 10) {
+				dm.evaluateThreshold("pg", testAlertType, "High number of active connections detected", value, levels)
+			}
+
+			resolved := n.sent[len(n.sent)-1]
+			if !resolved.IsResolved() {
+				t.Fatalf("última notificação em %s, esperado resolved", resolved.State)
+			}
+			if !slices.Equal(resolved.NotifiedSeverities, tt.want) {
+				t.Fatalf("severidades notificadas = %v, esperado %v", resolved.NotifiedSeverities, tt.want)
+			}
+		})
+	}
+}
+
+func TestUndeliveredNotificationIsRetried(t *testing.T) {
+	n := &testNotifier{down: true}
+	dm := newTestMonitor(n)
+	levels := config.ThresholdLevels{Warning: 60, Critical: 80}
+
+	dm.evaluateThreshold("pg", testAlertType, "High number of active connections detected", 70, levels)
+	status, _ := dm.testStatus()
+	if status.State != notifier.AlertStatePending || status.Notifications != 0 || status.LastNotifiedAt != nil {
+		t.Fatalf("após falha: estado %s, %d notificações; esperado pending sem notificação", status.State, status.Notifications)
+	}
+
+	n.down = false
+	dm.evaluateThreshold("pg", testAlertType, "High number of active connections detected", 70, levels)
+	status, _ = dm.testStatus()
+	if status.State != notifier.AlertStateFiring || status.Notifications != 1 || len(n.sent) != 1 {
+		t.Fatalf("após recuperar: estado %s, %d notificações, %d entregues", status.State, status.Notifications, len(n.sent))
+	}
+}
+
+func TestSilencedAlertIsNotifiedWhenSilenceEnds(t *testing.T) {
+	n := &testNotifier{}
+	dm := newTestMonitor(n)
+	levels := config.ThresholdLevels{Warning: 60, Critical: 80}
+
+	silence, err := dm.AddSilence(Silence{
+		Databases: []string{"pg"},
+		CreatedBy: "dba",
+		EndsAt:    time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		dm.evaluateThreshold("pg", testAlertType, "High number of active connections detected", 70, levels)
+	}
+	status, _ := dm.testStatus()
+	if len(n.sent) != 0 || status.Notifications != 0 {
+		t.Fatalf("silenciado: %d entregues, %d notificações", len(n.sent), status.Notifications)
+	}
+	if status.SilencedBy != silence.ID || status.SuppressedNotifications != 1 {
+		t.Fatalf("silenced_by = %q, %d supressões; esperado %q e 1", status.SilencedBy, status.SuppressedNotifications, silence.ID)
+	}
+
+	if !dm.DeleteSilence(silence.ID) {
+		t.Fatal("silence não encontrado")
+	}
+	for range 2 {
+		dm.evaluateThreshold("pg", testAlertType, "High number of active connections detected", 70, levels)
+	}
+	status, _ = dm.testStatus()
+	if len(n.sent) != 1 || status.State != notifier.AlertStateFiring || status.SilencedBy != "" {
+		t.Fatalf("após o silence: %d entregues, estado %s, silenced_by %q", len(n.sent), status.State, status.SilencedBy)
+	}
+}
+
+func TestResolveLevels(t *testing.T) {
+	tests := []struct {
+		name           string
+		absolute       config.ThresholdLevels
+		pct            config.ThresholdLevels
+		maxConnections int
+		want           config.ThresholdLevels
+	}{
+		{
+			name:           "percentual mais restritivo traz o próprio clear",
+			absolute:       config.ThresholdLevels{Warning: 90, Critical: 150, Clear: 40},
+			pct:            config.ThresholdLevels{Warning: 50, Critical: 80, Clear: 45},
+			maxConnections: 100,
+			want:           config.ThresholdLevels{Warning: 50, Critical: 80, Clear: 45},
+		},
+		{
+			name:           "absoluto mais restritivo mantém o próprio clear",
+			absolute:       config.ThresholdLevels{Warning: 40, Critical: 150, Clear: 30},
+			pct:            config.ThresholdLevels{Warning: 50, Critical: 80, Clear: 20},
+			maxConnections: 100,
+			want:           config.ThresholdLevels{Warning: 40, Critical: 80, Clear: 30},
+		},
+		{
+			name:           "empate fica com o absoluto",
+			absolute:       config.ThresholdLevels{Warning: 50, Clear: 30},
+			pct:            config.ThresholdLevels{Warning: 50, Clear: 40},
+			maxConnections: 100,
+			want:           config.ThresholdLevels{Warning: 50, Clear: 30},
+		},
+		{
+			name:           "só critical decide pelo critical",
+			absolute:       config.ThresholdLevels{Critical: 150, Clear: 100},
+			pct:            config.ThresholdLevels{Critical: 80, Clear: 60},
+			maxConnections: 100,
+			want:           config.ThresholdLevels{Critical: 80, Clear: 60},
+		},
+		{
+			name:           "hold é o maior dos dois lados",
+			absolute:       config.ThresholdLevels{Warning: 90, For: 60},
+			pct:            config.ThresholdLevels{Warning: 50, For: 30, ForChecks: 3},
+			maxConnections: 100,
+			want:           config.ThresholdLevels{Warning: 50, For: 60, ForChecks: 3},
+		},
+		{
+			name:     "sem max_connections mantém o hold dos percentuais",
+			absolute: config.ThresholdLevels{Warning: 90, Clear: 70},
+			pct:      config.ThresholdLevels{Warning: 50, Clear: 40, For: 120, ForChecks: 2},
+			want:     config.ThresholdLevels{Warning: 90, Clear: 70, For: 120, ForChecks: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveLevels(tt.absolute, tt.pct, tt.maxConnections); got != tt.want {
+				t.Fatalf("resolveLevels = %+v, esperado %+v", got, tt.want)
+			}
+		})
+	}
+}
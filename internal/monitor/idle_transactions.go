@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"dbMonitor/internal/config"
+	"dbMonitor/internal/database"
+	"dbMonitor/internal/notifier"
+)
+
+func (dm *DatabaseMonitor) checkIdleTransactions(cfg config.DatabaseConfig, sessions []database.IdleTransaction) {
+	levels := dm.config.EffectiveThresholds(cfg).IdleInTransactionSeconds
+
+	if len(sessions) == 0 {
+		dm.clearAlert(cfg.Name, "IDLE_IN_TRANSACTION")
+		return
+	}
+
+	// A consulta ordena por state_change, então a primeira é a mais antiga
+	oldest := sessions[0]
+	message := fmt.Sprintf("%d sessions idle in transaction. Oldest: session %d (user %s, client %s, application %s) idle for %s\n  Last query: %s",
+		len(sessions), oldest.ID, oldest.User, oldest.ClientAddr, oldest.ApplicationName,
+		(time.Duration(oldest.IdleSeconds) * time.Second).String(), oldest.Query)
+
+	dm.evaluateThreshold(cfg.Name, "IDLE_IN_TRANSACTION", message, int(oldest.IdleSeconds), levels)
+}
+
+// enforceIdleTransactionPolicy encerra as sessões acima do limite da política.
+// Em dry_run, cada período ocioso é registrado e notificado uma única vez.
+func (dm *DatabaseMonitor) enforceIdleTransactionPolicy(ctx context.Context, conn *database.Connection, cfg config.DatabaseConfig, sessions []database.IdleTransaction) {
+	policy := cfg.IdleTransactionPolicy
+	if !policy.Enabled {
+		return
+	}
+
+	reported := make(map[string]bool)
+
+	for _, session := range sessions {
+		if session.IdleSeconds < float64(policy.TerminateAfter) {
+			continue
+		}
+		if policy.IsAllowed(session.User, session.ApplicationName) {
+			continue
+		}
+
+		details := fmt.Sprintf("session %d (user %s, client %s, application %s) idle in transaction for %s\n  Last query: %s",
+			session.ID, session.User, session.ClientAddr, session.ApplicationName,
+			(time.Duration(session.IdleSeconds) * time.Second).String(), session.Query)
+
+		if policy.DryRun {
+			key := fmt.Sprintf("%d_%d", session.ID, session.StateChange.UnixNano())
+			reported[key] = true
+
+			dm.mu.RLock()
+			alreadyReported := dm.dryRunReported[cfg.Name][key]
+			dm.mu.RUnlock()
+			if alreadyReported {
+				continue
+			}
+
+			log.Printf("[dry-run] Would terminate %s on %s", details, cfg.Name)
+			dm.sendAlert(notifier.Alert{
+				DatabaseName: cfg.Name,
+				AlertType:    "SESSION_TERMINATION_DRY_RUN",
+				Severity:     notifier.SeverityWarning,
+				Message:      "Dry run: would terminate " + details,
+				Value:        int(session.IdleSeconds),
+				Threshold:    policy.TerminateAfter,
+				Timestamp:    time.Now(),
+			})
+			continue
+		}
+
+		if err := conn.TerminateSession(ctx, session); err != nil {
+			log.Printf("Failed to terminate %s on %s: %v", details, cfg.Name, err)
+			continue
+		}
+
+		log.Printf("Terminated %s on %s", details, cfg.Name)
+		dm.sendAlert(notifier.Alert{
+			DatabaseName: cfg.Name,
+			AlertType:    "SESSION_TERMINATED",
+			Severity:     notifier.SeverityWarning,
+			Message:      "Terminated " + details,
+			Value:        int(session.IdleSeconds),
+			Threshold:    policy.TerminateAfter,
+			Timestamp:    time.Now(),
+		})
+	}
+
+	dm.mu.Lock()
+	dm.dryRunReported[cfg.Name] = reported
+	dm.mu.Unlock()
+}
@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"dbMonitor/internal/config"
+	"dbMonitor/internal/database"
+)
+
+func (dm *DatabaseMonitor) checkBlockedSessions(cfg config.DatabaseConfig, tree *database.BlockingTree) {
+	levels := dm.config.EffectiveThresholds(cfg).BlockedSessionSeconds
+
+	var waiter *database.BlockingSession
+	if tree != nil {
+		waiter = tree.LongestWaiter()
+	}
+
+	if waiter == nil {
+		dm.clearAlert(cfg.Name, "BLOCKED_SESSION")
+		return
+	}
+
+	message := fmt.Sprintf("%d blocked sessions detected. Session %d (user %s, client %s) has been waiting %.0fs",
+		tree.BlockedCount, waiter.ID, waiter.User, waiter.ClientAddr, waiter.WaitSeconds)
+	if waiter.LockedObject != "" {
+		message += fmt.Sprintf(" on %s", waiter.LockedObject)
+	}
+
+	if head := tree.HeadBlocker(waiter.ID); head != nil && head.ID != waiter.ID {
+		message += fmt.Sprintf("\nHead blocker: session %d (user %s, client %s, application %s, state %s)\n  Query: %s",
+			head.ID, head.User, head.ClientAddr, head.ApplicationName, head.State, head.Query)
+	}
+
+	dm.evaluateThreshold(cfg.Name, "BLOCKED_SESSION", message, int(waiter.WaitSeconds), levels)
+}
+
+// GetBlockingTrees consulta a árvore de bloqueios atual de cada base que já
+// tem conexão aberta no pool.
+func (dm *DatabaseMonitor) GetBlockingTrees(ctx context.Context) map[string]*database.BlockingTree {
+	trees := make(map[string]*database.BlockingTree)
+
+	for _, cfg := range dm.config.Databases {
+		conn, ok := dm.pool.Connection(cfg.Name)
+		if !ok {
+			continue
+		}
+
+		tree, err := conn.GetBlockingTree(ctx)
+		if err != nil {
+			log.Printf("Failed to get blocking tree for %s: %v", cfg.Name, err)
+			continue
+		}
+		if tree != nil {
+			trees[cfg.Name] = tree
+		}
+	}
+
+	return trees
+}
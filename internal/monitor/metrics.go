@@ -0,0 +1,270 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"dbMonitor/internal/database"
+	"dbMonitor/internal/notifier"
+)
+
+type metricSample struct {
+	labels map[string]string
+	value  float64
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	typ     string
+	samples []metricSample
+}
+
+type metricsRegistry struct {
+	families map[string]*metricFamily
+}
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		families: make(map[string]*metricFamily),
+	}
+}
+
+func (r *metricsRegistry) add(name, help, typ string, labels map[string]string, value float64) {
+	family, exists := r.families[name]
+	if !exists {
+		family = &metricFamily{name: name, help: help, typ: typ}
+		r.families[name] = family
+	}
+	family.samples = append(family.samples, metricSample{labels: labels, value: value})
+}
+
+func (r *metricsRegistry) gauge(name, help string, labels map[string]string, value float64) {
+	r.add(name, help, "gauge", labels, value)
+}
+
+func (r *metricsRegistry) counter(name, help string, labels map[string]string, value float64) {
+	r.add(name, help, "counter", labels, value)
+}
+
+func (r *metricsRegistry) write(w io.Writer) error {
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		family := r.families[name]
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", family.name, family.help, family.name, family.typ); err != nil {
+			return err
+		}
+		for _, sample := range family.samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", family.name, formatLabels(sample.labels), formatValue(sample.value)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", k, escapeLabelValue(labels[k])))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabelValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
+
+func formatValue(value float64) string {
+	return fmt.Sprintf("%g", value)
+}
+
+func sanitizeMetricName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// WriteMetrics escreve as estatísticas de sessão, do pool e os contadores de
+// alerta no formato de exposição de texto do Prometheus.
+func (dm *DatabaseMonitor) WriteMetrics(ctx context.Context, w io.Writer) error {
+	registry := newMetricsRegistry()
+
+	dbTypes := make(map[string]string)
+	for _, db := range dm.config.Databases {
+		dbTypes[db.Name] = db.Type
+	}
+
+	dbLabels := func(name string) map[string]string {
+		return map[string]string{"database": name, "type": dbTypes[name]}
+	}
+
+	for name, stats := range dm.GetLastStats() {
+		labels := dbLabels(name)
+		registry.gauge("dbmonitor_sessions_active", "Number of active sessions.", labels, float64(stats.Active))
+		registry.gauge("dbmonitor_sessions_inactive", "Number of inactive sessions.", labels, float64(stats.Inactive))
+		registry.gauge("dbmonitor_sessions_idle", "Number of idle sessions.", labels, float64(stats.Idle))
+		registry.gauge("dbmonitor_sessions_idle_in_txn", "Number of sessions idle in transaction.", labels, float64(stats.IdleInTxn))
+		registry.gauge("dbmonitor_sessions_waiting", "Number of waiting sessions.", labels, float64(stats.Waiting))
+		registry.gauge("dbmonitor_sessions_total", "Total number of sessions.", labels, float64(stats.Total))
+		registry.gauge("dbmonitor_max_connections", "Server max_connections setting.", labels, float64(stats.MaxConnections))
+
+		var longest float64
+		for _, q := range stats.LongQueries {
+			if q.DurationSeconds > longest {
+				longest = q.DurationSeconds
+			}
+		}
+		registry.gauge("dbmonitor_long_running_queries", "Number of queries running longer than the configured threshold.", labels, float64(len(stats.LongQueries)))
+		registry.gauge("dbmonitor_long_running_query_max_seconds", "Duration of the longest running query.", labels, longest)
+		registry.gauge("dbmonitor_idle_in_transaction_max_seconds", "Age of the oldest session idle in transaction.", labels, stats.OldestIdleInTxnSeconds)
+
+		if stats.Replication != nil {
+			writeReplicationMetrics(registry, labels, stats.Replication)
+		}
+	}
+
+	poolStats, err := dm.pool.GetAllStats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get pool stats: %w", err)
+	}
+
+	for name, stats := range poolStats {
+		writePoolMetrics(registry, dbLabels(name), stats)
+	}
+
+	for _, status := range dm.GetAlerts() {
+		if status.State == notifier.AlertStateResolved {
+			continue
+		}
+		labels := dbLabels(status.DatabaseName)
+		labels["alert_type"] = status.AlertType
+		registry.gauge("dbmonitor_alert_occurrences", "Number of checks in which the active alert condition was observed.", labels, float64(status.Occurrences))
+		registry.gauge("dbmonitor_alert_notifications", "Number of notifications sent for the active alert.", labels, float64(status.Notifications))
+	}
+
+	return registry.write(w)
+}
+
+func writePoolMetrics(registry *metricsRegistry, labels map[string]string, stats *database.PoolStats) {
+	dbStats := stats.ConnectionStats
+
+	healthy := 0.0
+	if stats.IsHealthy {
+		healthy = 1
+	}
+
+	registry.gauge("dbmonitor_pool_healthy", "Whether the monitoring connection is healthy (1) or not (0).", labels, healthy)
+	registry.gauge("dbmonitor_pool_max_open_connections", "Maximum number of open connections to the database.", labels, float64(dbStats.MaxOpenConnections))
+	registry.gauge("dbmonitor_pool_open_connections", "Number of established connections, both in use and idle.", labels, float64(dbStats.OpenConnections))
+	registry.gauge("dbmonitor_pool_in_use_connections", "Number of connections currently in use.", labels, float64(dbStats.InUse))
+	registry.gauge("dbmonitor_pool_idle_connections", "Number of idle connections.", labels, float64(dbStats.Idle))
+	registry.counter("dbmonitor_pool_wait_count_total", "Total number of connections waited for.", labels, float64(dbStats.WaitCount))
+	registry.counter("dbmonitor_pool_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", labels, dbStats.WaitDuration.Seconds())
+	registry.counter("dbmonitor_pool_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.", labels, float64(dbStats.MaxIdleClosed))
+	registry.counter("dbmonitor_pool_max_idle_time_closed_total", "Total number of connections closed due to SetConnMaxIdleTime.", labels, float64(dbStats.MaxIdleTimeClosed))
+	registry.counter("dbmonitor_pool_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.", labels, float64(dbStats.MaxLifetimeClosed))
+
+	for key, value := range stats.Extended {
+		metricName := "dbmonitor_extended_" + sanitizeMetricName(key)
+		help := fmt.Sprintf("Extended database statistic %s.", key)
+
+		if states, ok := value.(map[string]int); ok {
+			for state, count := range states {
+				stateLabels := copyLabels(labels)
+				stateLabels["state"] = state
+				registry.gauge(metricName, help, stateLabels, float64(count))
+			}
+			continue
+		}
+
+		if v, ok := toFloat(value); ok {
+			registry.gauge(metricName, help, labels, v)
+		}
+	}
+}
+
+func writeReplicationMetrics(registry *metricsRegistry, labels map[string]string, stats *database.ReplicationStats) {
+	roleLabels := copyLabels(labels)
+	roleLabels["role"] = stats.Role
+
+	registry.gauge("dbmonitor_replication_lag_seconds", "Worst replication lag in seconds.", roleLabels, stats.LagSeconds)
+	registry.gauge("dbmonitor_replication_lag_bytes", "Worst replication lag in bytes.", roleLabels, float64(stats.LagBytes))
+
+	if stats.Role == database.ReplicationRoleReplica {
+		running := 1.0
+		if stats.IsStopped() {
+			running = 0
+		}
+		registry.gauge("dbmonitor_replication_running", "Whether both MySQL replication threads are running (1) or not (0).", labels, running)
+		registry.gauge("dbmonitor_replication_gtid_gap", "Retrieved GTID transactions not yet executed.", labels, float64(stats.GTIDGap))
+	}
+
+	for _, replica := range stats.Replicas {
+		replicaLabels := copyLabels(labels)
+		replicaLabels["replica"] = replica.ApplicationName
+		replicaLabels["client_addr"] = replica.ClientAddr
+		registry.gauge("dbmonitor_replica_write_lag_seconds", "Replica write lag in seconds.", replicaLabels, replica.WriteLagSeconds)
+		registry.gauge("dbmonitor_replica_flush_lag_seconds", "Replica flush lag in seconds.", replicaLabels, replica.FlushLagSeconds)
+		registry.gauge("dbmonitor_replica_replay_lag_seconds", "Replica replay lag in seconds.", replicaLabels, replica.ReplayLagSeconds)
+		registry.gauge("dbmonitor_replica_replay_lag_bytes", "Bytes sent to the replica but not yet replayed.", replicaLabels, float64(replica.ReplayLagBytes))
+	}
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	c := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		c[k] = v
+	}
+	return c
+}
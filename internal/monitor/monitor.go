@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -19,18 +20,23 @@ type DatabaseMonitor struct {
 	notifier    notifier.Notifier
 	mu          sync.RWMutex
 	lastStats   map[string]*database.SessionStats
-	alertCounts map[string]int
-}
+	alertStates map[string]*AlertStatus
+
+	dryRunReported map[string]map[string]bool
+	history        map[string][]database.SessionStats
 
-type Alert struct {
-	DatabaseName string
-	AlertType    string
-	Message      string
-	Value        int
-	Threshold    int
-	Timestamp    time.Time
+	silences    map[string]*Silence
+	silenceSeq  int
+	maintenance []maintenanceWindow
+	suppressed  []SuppressedAlert
+
+	breaches map[string]*breachState
 }
 
+// historySize é o número de amostras mantidas por base para os gráficos dos
+// alertas.
+const historySize = 60
+
 func NewDatabaseMonitor(cfg *config.Config, notifier notifier.Notifier) *DatabaseMonitor {
 	pool := database.NewPool(cfg.Pool)
 
@@ -39,7 +45,15 @@ func NewDatabaseMonitor(cfg *config.Config, notifier notifier.Notifier) *Databas
 		pool:        pool,
 		notifier:    notifier,
 		lastStats:   make(map[string]*database.SessionStats),
-		alertCounts: make(map[string]int),
+		alertStates: make(map[string]*AlertStatus),
+
+		dryRunReported: make(map[string]map[string]bool),
+		history:        make(map[string][]database.SessionStats),
+
+		silences:    make(map[string]*Silence),
+		maintenance: newMaintenanceWindows(cfg.MaintenanceWindows),
+
+		breaches: make(map[string]*breachState),
 	}
 
 	go pool.StartHealthCheckRoutine(context.Background())
@@ -82,14 +96,16 @@ func (dm *DatabaseMonitor) checkInstance(ctx context.Context, cfg config.Databas
 	conn, err := dm.pool.GetConnection(cfg)
 	if err != nil {
 		log.Printf("Failed to get connection for %s: %v", cfg.Name, err)
-		dm.sendAlert(Alert{
+		dm.raiseAlert(notifier.Alert{
 			DatabaseName: cfg.Name,
 			AlertType:    "CONNECTION_ERROR",
+			Severity:     notifier.SeverityCritical,
 			Message:      fmt.Sprintf("Failed to establish connection: %v", err),
 			Timestamp:    time.Now(),
 		})
 		return err
 	}
+	dm.clearAlert(cfg.Name, "CONNECTION_ERROR")
 
 	statsCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
 	defer cancel()
@@ -97,139 +113,336 @@ func (dm *DatabaseMonitor) checkInstance(ctx context.Context, cfg config.Databas
 	stats, err := conn.GetSessionStats(statsCtx)
 	if err != nil {
 		log.Printf("Failed to get statistics for %s: %v", cfg.Name, err)
-		dm.sendAlert(Alert{
+		dm.raiseAlert(notifier.Alert{
 			DatabaseName: cfg.Name,
 			AlertType:    "QUERY_ERROR",
+			Severity:     notifier.SeverityCritical,
 			Message:      fmt.Sprintf("Failed to query statistics: %v", err),
 			Timestamp:    time.Now(),
 		})
 		return err
 	}
+	dm.clearAlert(cfg.Name, "QUERY_ERROR")
+
+	thresholds := dm.config.EffectiveThresholds(cfg)
+
+	replicationEnabled := thresholds.ReplicationLagSeconds.Lowest() > 0 || thresholds.ReplicationLagBytes.Lowest() > 0
+	replication, err := collectIf(replicationEnabled, func() (*database.ReplicationStats, error) {
+		return conn.GetReplicationStats(statsCtx)
+	})
+	if err != nil {
+		log.Printf("Failed to get replication statistics for %s: %v", cfg.Name, err)
+	}
+	stats.Replication = replication
+
+	minQuerySeconds := thresholds.LongRunningQuerySeconds.Lowest()
+	longQueries, longQueriesErr := collectIf(minQuerySeconds > 0, func() ([]database.LongRunningQuery, error) {
+		return conn.GetLongRunningQueries(statsCtx, minQuerySeconds)
+	})
+	if longQueriesErr != nil {
+		log.Printf("Failed to get long running queries for %s: %v", cfg.Name, longQueriesErr)
+	}
+	stats.LongQueries = longQueries
+
+	blockingTree, blockingErr := collectIf(thresholds.BlockedSessionSeconds.Lowest() > 0, func() (*database.BlockingTree, error) {
+		return conn.GetBlockingTree(statsCtx)
+	})
+	if blockingErr != nil {
+		log.Printf("Failed to get blocking sessions for %s: %v", cfg.Name, blockingErr)
+	}
+
+	idleTxnsEnabled := thresholds.IdleInTransactionSeconds.Lowest() > 0 || cfg.IdleTransactionPolicy.Enabled
+	idleTxns, idleTxnsErr := collectIf(idleTxnsEnabled, func() ([]database.IdleTransaction, error) {
+		return conn.GetIdleTransactions(statsCtx)
+	})
+	if idleTxnsErr != nil {
+		log.Printf("Failed to get idle in transaction sessions for %s: %v", cfg.Name, idleTxnsErr)
+	}
+	if len(idleTxns) > 0 {
+		stats.OldestIdleInTxnSeconds = idleTxns[0].IdleSeconds
+	}
 
 	dm.mu.Lock()
 	dm.lastStats[cfg.Name] = stats
+	dm.recordHistory(stats)
 	dm.mu.Unlock()
 
 	log.Printf("DB: %s | Total: %d | Active: %d | Inactive: %d | Idle: %d | Waiting: %d",
 		stats.DatabaseName, stats.Total, stats.Active, stats.Inactive, stats.Idle, stats.Waiting)
+	if replication != nil {
+		log.Printf("DB: %s | Replication role: %s | Lag: %.0fs | Lag bytes: %d",
+			stats.DatabaseName, replication.Role, replication.LagSeconds, replication.LagBytes)
+	}
 
-	dm.checkThresholds(stats)
+	dm.checkThresholds(cfg, stats)
+	if longQueriesErr == nil {
+		dm.checkLongRunningQueries(cfg, longQueries)
+	}
+	if blockingErr == nil {
+		dm.checkBlockedSessions(cfg, blockingTree)
+	}
+	if idleTxnsErr == nil {
+		dm.checkIdleTransactions(cfg, idleTxns)
+		dm.enforceIdleTransactionPolicy(statsCtx, conn, cfg, idleTxns)
+	}
 
 	return nil
 }
 
-func (dm *DatabaseMonitor) checkThresholds(stats *database.SessionStats) {
-	thresholds := dm.config.Thresholds
-	alertKey := stats.DatabaseName
-
-	if stats.Active > thresholds.ActiveConnections {
-		if dm.shouldSendAlert(alertKey, "HIGH_ACTIVE_CONNECTIONS") {
-			dm.sendAlert(Alert{
-				DatabaseName: stats.DatabaseName,
-				AlertType:    "HIGH_ACTIVE_CONNECTIONS",
-				Message:      "High number of active connections detected",
-				Value:        stats.Active,
-				Threshold:    thresholds.ActiveConnections,
-				Timestamp:    time.Now(),
-			})
+// collectIf só executa uma coleta opcional quando algum threshold ou política
+// da base usa o resultado, poupando consultas ao servidor.
+func collectIf[T any](enabled bool, collect func() (T, error)) (T, error) {
+	if !enabled {
+		var zero T
+		return zero, nil
+	}
+	return collect()
+}
+
+func (dm *DatabaseMonitor) checkThresholds(cfg config.DatabaseConfig, stats *database.SessionStats) {
+	thresholds := dm.config.EffectiveThresholds(cfg)
+
+	checks := []struct {
+		alertType string
+		message   string
+		value     int
+		levels    config.ThresholdLevels
+		pct       config.ThresholdLevels
+	}{
+		{"HIGH_ACTIVE_CONNECTIONS", "High number of active connections detected", stats.Active, thresholds.ActiveConnections, thresholds.ActiveConnectionsPct},
+		{"HIGH_INACTIVE_CONNECTIONS", "High number of inactive connections detected", stats.Inactive, thresholds.InactiveConnections, thresholds.InactiveConnectionsPct},
+		{"HIGH_TOTAL_CONNECTIONS", "High total number of connections detected", stats.Total, thresholds.TotalConnections, thresholds.TotalConnectionsPct},
+	}
+
+	for _, check := range checks {
+		levels := resolveLevels(check.levels, check.pct, stats.MaxConnections)
+
+		message := check.message
+		if stats.MaxConnections > 0 {
+			message = fmt.Sprintf("%s (%d%% of max_connections %d)",
+				message, check.value*100/stats.MaxConnections, stats.MaxConnections)
 		}
+
+		dm.evaluateThreshold(stats.DatabaseName, check.alertType, message, check.value, levels)
 	}
 
-	if stats.Inactive > thresholds.InactiveConnections {
-		if dm.shouldSendAlert(alertKey, "HIGH_INACTIVE_CONNECTIONS") {
-			dm.sendAlert(Alert{
-				DatabaseName: stats.DatabaseName,
-				AlertType:    "HIGH_INACTIVE_CONNECTIONS",
-				Message:      "High number of inactive connections detected",
-				Value:        stats.Inactive,
-				Threshold:    thresholds.InactiveConnections,
-				Timestamp:    time.Now(),
-			})
+	if stats.Replication != nil {
+		dm.checkReplication(stats.DatabaseName, thresholds, stats.Replication)
+	}
+}
+
+// breachState acompanha há quanto tempo, e por quantas verificações seguidas,
+// um threshold está sendo violado.
+type breachState struct {
+	since  time.Time
+	checks int
+}
+
+// evaluateThreshold dispara ou resolve o alerta conforme o valor atual
+// ultrapasse os níveis informados. Com for/for_checks, o alerta fica pending
+// até a violação se sustentar; com clear, um alerta firing só é resolvido
+// quando o valor fica abaixo de clear.
+func (dm *DatabaseMonitor) evaluateThreshold(databaseName, alertType, message string, value int, levels config.ThresholdLevels) {
+	key := alertKey(databaseName, alertType)
+	now := time.Now()
+	severity, threshold, breached := evaluateLevels(value, levels)
+
+	if !breached {
+		dm.mu.Lock()
+		status, exists := dm.alertStates[key]
+		if exists && status.State == notifier.AlertStateFiring && levels.Clear > 0 && value >= levels.Clear {
+			// Entre clear e o nível de disparo o alerta continua ativo, sem
+			// renotificar.
+			status.Value = value
+			status.LastSeenAt = now
+			dm.mu.Unlock()
+			return
 		}
+		dm.mu.Unlock()
+
+		dm.clearAlert(databaseName, alertType)
+		return
 	}
 
-	if stats.Total > thresholds.TotalConnections {
-		if dm.shouldSendAlert(alertKey, "HIGH_TOTAL_CONNECTIONS") {
-			if dm.shouldSendAlert(alertKey, "HIGH_TOTAL_CONNECTIONS") {
-				dm.sendAlert(Alert{
-					DatabaseName: stats.DatabaseName,
-					AlertType:    "HIGH_TOTAL_CONNECTIONS",
-					Message:      "High total number of connections detected",
-					Value:        stats.Total,
-					Threshold:    thresholds.TotalConnections,
-					Timestamp:    time.Now(),
-				})
-			}
+	dm.mu.Lock()
+	breach, exists := dm.breaches[key]
+	if !exists {
+		breach = &breachState{since: now}
+		dm.breaches[key] = breach
+	}
+	breach.checks++
+	sustained := (levels.For == 0 || now.Sub(breach.since) >= time.Duration(levels.For)*time.Second) &&
+		(levels.ForChecks == 0 || breach.checks >= levels.ForChecks)
+	dm.mu.Unlock()
+
+	alert := notifier.Alert{
+		DatabaseName: databaseName,
+		AlertType:    alertType,
+		Severity:     severity,
+		Message:      message,
+		Value:        value,
+		Threshold:    threshold,
+		Timestamp:    now,
+	}
+
+	if !sustained {
+		dm.holdAlert(alert)
+		return
+	}
+
+	dm.raiseAlert(alert)
+}
+
+// resolveLevels converte os thresholds percentuais em valores absolutos a
+// partir do max_connections atual e mantém, em cada nível, o mais restritivo.
+// O clear vem do mesmo lado (absoluto ou percentual) do nível de disparo mais
+// baixo escolhido, para a histerese ficar coerente com ele. Sem
+// max_connections conhecido, apenas os níveis absolutos são considerados, mas
+// o for/for_checks dos percentuais continua valendo.
+func resolveLevels(absolute, pct config.ThresholdLevels, maxConnections int) config.ThresholdLevels {
+	var fromPct config.ThresholdLevels
+	if maxConnections > 0 {
+		fromPct = config.ThresholdLevels{
+			Warning:  pct.Warning * maxConnections / 100,
+			Critical: pct.Critical * maxConnections / 100,
+			Clear:    pct.Clear * maxConnections / 100,
 		}
 	}
+
+	levels := config.ThresholdLevels{
+		Warning:   stricterLevel(absolute.Warning, fromPct.Warning),
+		Critical:  stricterLevel(absolute.Critical, fromPct.Critical),
+		Clear:     absolute.Clear,
+		For:       max(absolute.For, pct.For),
+		ForChecks: max(absolute.ForChecks, pct.ForChecks),
+	}
+
+	usesPct := fromPct.Warning > 0 && levels.Warning != absolute.Warning
+	if levels.Warning == 0 {
+		usesPct = fromPct.Critical > 0 && levels.Critical != absolute.Critical
+	}
+	if usesPct {
+		levels.Clear = fromPct.Clear
+	}
+
+	return levels
+}
+
+func stricterLevel(a, b int) int {
+	if a == 0 {
+		return b
+	}
+	if b == 0 || a < b {
+		return a
+	}
+	return b
+}
+
+// evaluateLevels retorna a severidade e o threshold ultrapassado pelo valor,
+// dando prioridade ao nível critical.
+func evaluateLevels(value int, levels config.ThresholdLevels) (notifier.Severity, int, bool) {
+	if levels.Critical > 0 && value > levels.Critical {
+		return notifier.SeverityCritical, levels.Critical, true
+	}
+	if levels.Warning > 0 && value > levels.Warning {
+		return notifier.SeverityWarning, levels.Warning, true
+	}
+	return "", 0, false
+}
+
+// recordHistory deve ser chamado com dm.mu travado.
+func (dm *DatabaseMonitor) recordHistory(stats *database.SessionStats) {
+	sample := *stats
+	sample.Replication = nil
+	sample.LongQueries = nil
+
+	history := append(dm.history[stats.DatabaseName], sample)
+	if len(history) > historySize {
+		history = history[len(history)-historySize:]
+	}
+	dm.history[stats.DatabaseName] = history
+}
+
+func (dm *DatabaseMonitor) databaseConfig(name string) (config.DatabaseConfig, bool) {
+	for _, db := range dm.config.Databases {
+		if db.Name == name {
+			return db, true
+		}
+	}
+	return config.DatabaseConfig{}, false
 }
 
-func (dm *DatabaseMonitor) shouldSendAlert(databaseName, alertType string) bool {
+// sendAlert notifica o alerta e informa se ele foi entregue a algum canal:
+// retorna false quando um silence o suprimiu ou quando todos os canais
+// falharam, para que a notificação seja tentada de novo na próxima verificação.
+func (dm *DatabaseMonitor) sendAlert(alert notifier.Alert) bool {
+	if alert.State == "" {
+		alert.State = notifier.AlertStateFiring
+	}
+	if alert.StartedAt.IsZero() {
+		alert.StartedAt = alert.Timestamp
+	}
+	alert.HealthCheckInterval = dm.config.Pool.HealthCheckInterval
+
+	db, hasConfig := dm.databaseConfig(alert.DatabaseName)
+	if hasConfig {
+		alert.DatabaseType = db.Type
+		alert.Labels = make(map[string]string, len(db.Labels))
+		for k, v := range db.Labels {
+			alert.Labels[k] = v
+		}
+	}
+
+	if silenceID, silenced := dm.activeSilence(alert); silenced {
+		dm.recordSuppressed(alert, silenceID)
+		log.Printf("Alert suppressed for %s: %s (%s) by silence %s", alert.DatabaseName, alert.AlertType, alert.State, silenceID)
+		return false
+	}
+
 	dm.mu.Lock()
-	defer dm.mu.Unlock()
+	if status, ok := dm.alertStates[alertKey(alert.DatabaseName, alert.AlertType)]; ok {
+		status.SilencedBy = ""
+	}
+	dm.mu.Unlock()
 
-	key := fmt.Sprintf("%s_%s", databaseName, alertType)
-	count := dm.alertCounts[key]
-	frequency := dm.config.Application.AlertFrequency
+	dm.mu.RLock()
+	if stats, ok := dm.lastStats[alert.DatabaseName]; ok {
+		statsCopy := *stats
+		alert.Stats = &statsCopy
+	}
+	alert.History = append([]database.SessionStats(nil), dm.history[alert.DatabaseName]...)
+	dm.mu.RUnlock()
+
+	if hasConfig {
+		thresholds := dm.config.EffectiveThresholds(db)
+		maxConnections := 0
+		if alert.Stats != nil {
+			maxConnections = alert.Stats.MaxConnections
+		}
+		alert.Thresholds = map[string]config.ThresholdLevels{
+			"active":   resolveLevels(thresholds.ActiveConnections, thresholds.ActiveConnectionsPct, maxConnections),
+			"inactive": resolveLevels(thresholds.InactiveConnections, thresholds.InactiveConnectionsPct, maxConnections),
+			"total":    resolveLevels(thresholds.TotalConnections, thresholds.TotalConnectionsPct, maxConnections),
+		}
+	}
 
-	if count == 0 || (frequency > 0 && count%frequency == 0) {
-		dm.alertCounts[key] = count + 1
+	err := dm.notifier.SendAlert(context.Background(), alert)
+	if err == nil {
+		log.Printf("Alert sent for %s: %s (%s)", alert.DatabaseName, alert.AlertType, alert.State)
 		return true
 	}
 
-	dm.alertCounts[key] = count + 1
-	return false
-}
+	log.Printf("Failed to send alert for %s: %v", alert.DatabaseName, err)
 
-func (dm *DatabaseMonitor) sendAlert(alert Alert) {
-	subject := fmt.Sprintf("DB Monitor ALERT: %s - %s", alert.DatabaseName, alert.AlertType)
-
-	var body string
-	if alert.Value > 0 && alert.Threshold > 0 {
-		body = fmt.Sprintf(`
-DATABASE MONITORING ALERT
-
-Database: %s
-Alert Type: %s
-Message: %s
-Current Value: %d
-Configured Threshold: %d
-Timestamp: %s
-
-This is an automated alert from the database monitoring system.
-Please check the database status immediately.
-
-Connection Pool Information:
-- Pool connections are managed automatically
-- Unhealthy connections are automatically recreated
-- Health checks run every %d seconds
-		`, alert.DatabaseName, alert.AlertType, alert.Message,
-			alert.Value, alert.Threshold, alert.Timestamp.Format("2006-01-02 15:04:05"), dm.config.Pool.HealthCheckInterval)
-	} else {
-		body = fmt.Sprintf(`
-DATABASE MONITORING ALERT
-
-Database: %s
-Alert Type: %s
-Message: %s
-Timestamp: %s
-
-This is an automated alert from the database monitoring system.
-Please check the database status immediately.
-
-Connection Pool Information:
-- Pool connections are managed automatically
-- Unhealthy connections are automatically recreated
-- Health checks run every %d seconds
-		`, alert.DatabaseName, alert.AlertType, alert.Message,
-			alert.Timestamp.Format("2006-01-02 15:04:05"), dm.config.Pool.HealthCheckInterval)
-	}
-
-	if err := dm.notifier.SendAlert(subject, body); err != nil {
-		log.Printf("Failed to send alert for %s: %v", alert.DatabaseName, err)
-	} else {
-		log.Printf("Alert sent for %s: %s", alert.DatabaseName, alert.AlertType)
+	var deliveryErr *notifier.DeliveryError
+	if errors.As(err, &deliveryErr) {
+		for _, result := range deliveryErr.Results {
+			if !result.Failed() {
+				return true
+			}
+		}
 	}
+	return false
 }
 
 func (dm *DatabaseMonitor) GetLastStats() map[string]*database.SessionStats {
@@ -253,24 +466,6 @@ func (dm *DatabaseMonitor) HealthCheck(ctx context.Context) map[string]error {
 	return dm.pool.HealthCheck(ctx)
 }
 
-func (dm *DatabaseMonitor) ResetAlertCounts() {
-	dm.mu.Lock()
-	defer dm.mu.Unlock()
-	dm.alertCounts = make(map[string]int)
-	log.Println("Alert counts reset")
-}
-
-func (dm *DatabaseMonitor) GetAlertCounts() map[string]int {
-	dm.mu.RLock()
-	defer dm.mu.RUnlock()
-
-	counts := make(map[string]int)
-	for k, v := range dm.alertCounts {
-		counts[k] = v
-	}
-	return counts
-}
-
 func (dm *DatabaseMonitor) Close() error {
 	dm.mu.Lock()
 	defer dm.mu.Unlock()
@@ -281,7 +476,8 @@ func (dm *DatabaseMonitor) Close() error {
 	}
 
 	dm.lastStats = make(map[string]*database.SessionStats)
-	dm.alertCounts = make(map[string]int)
+	dm.alertStates = make(map[string]*AlertStatus)
+	dm.history = make(map[string][]database.SessionStats)
 
 	log.Println("Database monitor closed successfully")
 	return nil
@@ -0,0 +1,42 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dbMonitor/internal/config"
+	"dbMonitor/internal/database"
+)
+
+const maxReportedQueries = 5
+
+func (dm *DatabaseMonitor) checkLongRunningQueries(cfg config.DatabaseConfig, queries []database.LongRunningQuery) {
+	levels := dm.config.EffectiveThresholds(cfg).LongRunningQuerySeconds
+
+	var longest float64
+	for _, q := range queries {
+		if q.DurationSeconds > longest {
+			longest = q.DurationSeconds
+		}
+	}
+
+	dm.evaluateThreshold(cfg.Name, "LONG_RUNNING_QUERY", formatLongRunningQueries(queries), int(longest), levels)
+}
+
+func formatLongRunningQueries(queries []database.LongRunningQuery) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d long running queries detected", len(queries))
+
+	for i, q := range queries {
+		if i == maxReportedQueries {
+			fmt.Fprintf(&b, "\n- ... and %d more", len(queries)-maxReportedQueries)
+			break
+		}
+		fmt.Fprintf(&b, "\n- ID: %d | User: %s | Client: %s | Application: %s | Duration: %s\n  Query: %s",
+			q.ID, q.User, q.ClientAddr, q.ApplicationName,
+			(time.Duration(q.DurationSeconds) * time.Second).String(), q.Query)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"dbMonitor/internal/config"
+	"dbMonitor/internal/database"
+	"dbMonitor/internal/notifier"
+)
+
+func (dm *DatabaseMonitor) checkReplication(databaseName string, thresholds config.ThresholdConfig, stats *database.ReplicationStats) {
+	if stats.IsStopped() {
+		dm.raiseAlert(notifier.Alert{
+			DatabaseName: databaseName,
+			AlertType:    "REPLICATION_STOPPED",
+			Severity:     notifier.SeverityCritical,
+			Message: fmt.Sprintf("Replication thread stopped (IO: %s, SQL: %s). Last IO error: %q. Last SQL error: %q",
+				stats.IOThreadRunning, stats.SQLThreadRunning, stats.LastIOError, stats.LastSQLError),
+			Timestamp: time.Now(),
+		})
+	} else {
+		dm.clearAlert(databaseName, "REPLICATION_STOPPED")
+	}
+
+	dm.evaluateThreshold(databaseName, "REPLICATION_LAG",
+		fmt.Sprintf("High replication lag detected on %s (%.0fs)", stats.Role, stats.LagSeconds),
+		int(stats.LagSeconds), thresholds.ReplicationLagSeconds)
+
+	dm.evaluateThreshold(databaseName, "REPLICATION_LAG_BYTES",
+		fmt.Sprintf("High replication lag detected on %s (%d bytes)", stats.Role, stats.LagBytes),
+		int(stats.LagBytes), thresholds.ReplicationLagBytes)
+}
@@ -0,0 +1,252 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"dbMonitor/internal/config"
+	"dbMonitor/internal/notifier"
+	"dbMonitor/internal/schedule"
+)
+
+// suppressedHistorySize é o número de notificações suprimidas mantidas para
+// consulta.
+const suppressedHistorySize = 100
+
+// Silence suprime as notificações dos alertas que casarem com os critérios
+// entre StartsAt e EndsAt. Os alertas continuam sendo avaliados e registrados.
+type Silence struct {
+	ID         string            `json:"id"`
+	Databases  []string          `json:"databases,omitempty"`
+	AlertTypes []string          `json:"alert_types,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	StartsAt   time.Time         `json:"starts_at"`
+	EndsAt     time.Time         `json:"ends_at"`
+	CreatedBy  string            `json:"created_by"`
+	Comment    string            `json:"comment"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Source     string            `json:"source"`
+}
+
+const (
+	SilenceSourceAPI         = "api"
+	SilenceSourceMaintenance = "maintenance_window"
+)
+
+func (s Silence) matcher() config.RouteMatch {
+	return config.RouteMatch{Databases: s.Databases, AlertTypes: s.AlertTypes, Labels: s.Labels}
+}
+
+func (s Silence) IsActive(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+func (s Silence) Matches(alert notifier.Alert) bool {
+	return s.matcher().Matches(alert.DatabaseName, alert.AlertType, string(alert.Severity), alert.Labels)
+}
+
+// SuppressedAlert registra uma notificação que deixou de ser enviada.
+type SuppressedAlert struct {
+	DatabaseName string              `json:"database_name"`
+	AlertType    string              `json:"alert_type"`
+	Severity     notifier.Severity   `json:"severity"`
+	State        notifier.AlertState `json:"state"`
+	Message      string              `json:"message"`
+	SilenceID    string              `json:"silence_id"`
+	Timestamp    time.Time           `json:"timestamp"`
+}
+
+type maintenanceWindow struct {
+	config   config.MaintenanceWindowConfig
+	cron     *schedule.Cron
+	location *time.Location
+}
+
+func newMaintenanceWindows(cfgs []config.MaintenanceWindowConfig) []maintenanceWindow {
+	windows := make([]maintenanceWindow, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		// A configuração já foi validada em config.Load.
+		cron, err := schedule.Parse(cfg.Schedule)
+		if err != nil {
+			log.Printf("Ignoring maintenance window %s: %v", cfg.Name, err)
+			continue
+		}
+		location := time.Local
+		if cfg.Timezone != "" {
+			if location, err = time.LoadLocation(cfg.Timezone); err != nil {
+				log.Printf("Ignoring maintenance window %s: %v", cfg.Name, err)
+				continue
+			}
+		}
+		windows = append(windows, maintenanceWindow{config: cfg, cron: cron, location: location})
+	}
+	return windows
+}
+
+// silence retorna a janela como Silence quando ela está ativa em now.
+func (w maintenanceWindow) silence(now time.Time) (Silence, bool) {
+	duration := time.Duration(w.config.Duration) * time.Minute
+	start, ok := w.cron.ActiveWindow(now.In(w.location), duration)
+	if !ok {
+		return Silence{}, false
+	}
+
+	return Silence{
+		ID:         "maintenance-" + w.config.Name,
+		Databases:  w.config.Databases,
+		AlertTypes: w.config.AlertTypes,
+		Labels:     w.config.Labels,
+		StartsAt:   start,
+		EndsAt:     start.Add(duration),
+		CreatedBy:  "config",
+		Comment:    w.config.Comment,
+		Source:     SilenceSourceMaintenance,
+	}, true
+}
+
+// AddSilence valida e registra um silence. Sem StartsAt, começa agora.
+func (dm *DatabaseMonitor) AddSilence(s Silence) (Silence, error) {
+	now := time.Now()
+	if s.StartsAt.IsZero() {
+		s.StartsAt = now
+	}
+
+	switch {
+	case s.CreatedBy == "":
+		return Silence{}, errors.New("created_by is required")
+	case len(s.Databases) == 0 && len(s.AlertTypes) == 0 && len(s.Labels) == 0:
+		return Silence{}, errors.New("at least one matcher (databases, alert_types or labels) is required")
+	case !s.EndsAt.After(s.StartsAt):
+		return Silence{}, errors.New("ends_at must be after starts_at")
+	case !s.EndsAt.After(now):
+		return Silence{}, errors.New("ends_at must be in the future")
+	}
+
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	dm.silenceSeq++
+	s.ID = fmt.Sprintf("%d-%d", now.Unix(), dm.silenceSeq)
+	s.CreatedAt = now
+	s.Source = SilenceSourceAPI
+	dm.silences[s.ID] = &s
+
+	log.Printf("Silence %s created by %s until %s: %s", s.ID, s.CreatedBy, s.EndsAt.Format(time.RFC3339), s.Comment)
+	return s, nil
+}
+
+// DeleteSilence remove um silence criado pela API. Janelas de manutenção só
+// mudam pela configuração.
+func (dm *DatabaseMonitor) DeleteSilence(id string) bool {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	if _, ok := dm.silences[id]; !ok {
+		return false
+	}
+	delete(dm.silences, id)
+
+	log.Printf("Silence %s deleted", id)
+	return true
+}
+
+// GetSilences lista os silences da API ainda não expirados e as janelas de
+// manutenção ativas no momento.
+func (dm *DatabaseMonitor) GetSilences() []Silence {
+	now := time.Now()
+
+	dm.mu.Lock()
+	dm.expireSilences(now)
+	silences := make([]Silence, 0, len(dm.silences))
+	for _, s := range dm.silences {
+		silences = append(silences, *s)
+	}
+	dm.mu.Unlock()
+
+	for _, w := range dm.maintenance {
+		if s, ok := w.silence(now); ok {
+			silences = append(silences, s)
+		}
+	}
+
+	sort.Slice(silences, func(i, j int) bool {
+		return silences[i].StartsAt.Before(silences[j].StartsAt)
+	})
+
+	return silences
+}
+
+// GetSuppressedAlerts retorna as notificações suprimidas mais recentes.
+func (dm *DatabaseMonitor) GetSuppressedAlerts() []SuppressedAlert {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	return append([]SuppressedAlert(nil), dm.suppressed...)
+}
+
+// expireSilences descarta os silences vencidos. Deve ser chamado com dm.mu.
+func (dm *DatabaseMonitor) expireSilences(now time.Time) {
+	for id, s := range dm.silences {
+		if !now.Before(s.EndsAt) {
+			delete(dm.silences, id)
+		}
+	}
+}
+
+// activeSilence retorna o silence ativo que casa com o alerta, se houver.
+func (dm *DatabaseMonitor) activeSilence(alert notifier.Alert) (string, bool) {
+	now := time.Now()
+
+	dm.mu.Lock()
+	dm.expireSilences(now)
+	for _, s := range dm.silences {
+		if s.IsActive(now) && s.Matches(alert) {
+			dm.mu.Unlock()
+			return s.ID, true
+		}
+	}
+	dm.mu.Unlock()
+
+	for _, w := range dm.maintenance {
+		if s, ok := w.silence(now); ok && s.Matches(alert) {
+			return s.ID, true
+		}
+	}
+
+	return "", false
+}
+
+// recordSuppressed guarda a notificação suprimida e marca o estado do alerta
+// com o silence responsável. Como a notificação suprimida volta a ser tentada
+// a cada verificação, só a primeira supressão de um alerta ativo por um mesmo
+// silence é registrada.
+func (dm *DatabaseMonitor) recordSuppressed(alert notifier.Alert, silenceID string) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	status, ok := dm.alertStates[alertKey(alert.DatabaseName, alert.AlertType)]
+	if ok && !alert.IsResolved() && status.SilencedBy == silenceID {
+		return
+	}
+
+	dm.suppressed = append(dm.suppressed, SuppressedAlert{
+		DatabaseName: alert.DatabaseName,
+		AlertType:    alert.AlertType,
+		Severity:     alert.Severity,
+		State:        alert.State,
+		Message:      alert.Message,
+		SilenceID:    silenceID,
+		Timestamp:    alert.Timestamp,
+	})
+	if len(dm.suppressed) > suppressedHistorySize {
+		dm.suppressed = dm.suppressed[len(dm.suppressed)-suppressedHistorySize:]
+	}
+
+	if ok {
+		status.SilencedBy = silenceID
+		status.SuppressedNotifications++
+	}
+}
@@ -0,0 +1,190 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dbMonitor/internal/config"
+	"dbMonitor/internal/database"
+	"dbMonitor/internal/templates"
+)
+
+func init() {
+	templates.RegisterSamples(sampleAlerts()...)
+}
+
+// sampleAlerts são um alerta disparado e o mesmo alerta resolvido, com todos
+// os campos preenchidos, executados pelos templates ao carregar a
+// configuração.
+func sampleAlerts() []interface{} {
+	now := time.Now()
+	lastReplay := now.Add(-12 * time.Second)
+	stats := &database.SessionStats{
+		Active:         120,
+		Inactive:       30,
+		Idle:           25,
+		IdleInTxn:      5,
+		Waiting:        3,
+		Total:          160,
+		MaxConnections: 200,
+		DatabaseName:   "postgres",
+		Timestamp:      now.Format(time.RFC3339),
+		Replication: &database.ReplicationStats{
+			Role:                "standby",
+			LagSeconds:          12,
+			LagBytes:            123456,
+			LastReplayTimestamp: &lastReplay,
+		},
+		LongQueries: []database.LongRunningQuery{
+			{ID: 4242, User: "app", ClientAddr: "10.0.0.5", DurationSeconds: 320, Query: "SELECT 1"},
+		},
+		OldestIdleInTxnSeconds: 90,
+	}
+
+	firing := Alert{
+		DatabaseName: "exemplo",
+		DatabaseType: "postgresql",
+		AlertType:    "HIGH_TOTAL_CONNECTIONS",
+		Severity:     SeverityCritical,
+		State:        AlertStateFiring,
+		Message:      "High total number of connections detected",
+		Value:        160,
+		Threshold:    150,
+		Labels:       map[string]string{"env": "prod"},
+		StartedAt:    now.Add(-15 * time.Minute),
+		Timestamp:    now,
+		Stats:        stats,
+		History:      []database.SessionStats{*stats, *stats},
+		Thresholds: map[string]config.ThresholdLevels{
+			"total": {Warning: 150, Critical: 180},
+		},
+		HealthCheckInterval: 30,
+	}
+
+	resolved := firing
+	resolved.State = AlertStateResolved
+	resolved.NotifiedSeverities = []Severity{SeverityCritical}
+
+	return []interface{}{firing, resolved}
+}
+
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+type AlertState string
+
+const (
+	AlertStatePending  AlertState = "pending"
+	AlertStateFiring   AlertState = "firing"
+	AlertStateResolved AlertState = "resolved"
+)
+
+// Alert é o contrato entre o monitor e os notifiers. Cada notifier monta a
+// própria representação (texto, payload JSON etc.) a partir destes campos.
+type Alert struct {
+	DatabaseName string                 `json:"database_name"`
+	DatabaseType string                 `json:"database_type"`
+	AlertType    string                 `json:"alert_type"`
+	Severity     Severity               `json:"severity"`
+	State        AlertState             `json:"state"`
+	Message      string                 `json:"message"`
+	Value        int                    `json:"value"`
+	Threshold    int                    `json:"threshold"`
+	Labels       map[string]string      `json:"labels,omitempty"`
+	StartedAt    time.Time              `json:"started_at"`
+	Timestamp    time.Time              `json:"timestamp"`
+	Stats        *database.SessionStats `json:"stats,omitempty"`
+
+	// History traz as amostras recentes da base, da mais antiga para a mais
+	// nova. Thresholds traz os níveis efetivos de active, inactive e total.
+	History    []database.SessionStats           `json:"-"`
+	Thresholds map[string]config.ThresholdLevels `json:"thresholds,omitempty"`
+
+	// NotifiedSeverities traz, na resolução, as severidades com que o alerta
+	// chegou a ser notificado. Os roteadores entregam a resolução a todos os
+	// canais que receberam alguma delas, mesmo que a severidade tenha mudado.
+	NotifiedSeverities []Severity `json:"notified_severities,omitempty"`
+
+	// HealthCheckInterval é o intervalo, em segundos, dos health checks do
+	// pool de conexões, citado no texto padrão do alerta.
+	HealthCheckInterval int `json:"health_check_interval,omitempty"`
+}
+
+func (a Alert) IsResolved() bool {
+	return a.State == AlertStateResolved
+}
+
+// routingSeverities são as severidades usadas para escolher os canais do
+// alerta: a atual ou, numa resolução, as que foram notificadas.
+func (a Alert) routingSeverities() []Severity {
+	if a.IsResolved() && len(a.NotifiedSeverities) > 0 {
+		return a.NotifiedSeverities
+	}
+	return []Severity{a.Severity}
+}
+
+// Subject é o título curto usado por email e pelas integrações de chat.
+func (a Alert) Subject() string {
+	if a.IsResolved() {
+		return fmt.Sprintf("DB Monitor RESOLVED: %s - %s", a.DatabaseName, a.AlertType)
+	}
+	return fmt.Sprintf("DB Monitor %s ALERT: %s - %s", strings.ToUpper(string(a.Severity)), a.DatabaseName, a.AlertType)
+}
+
+// truncate limita o texto a max caracteres, cortando entre runes para não
+// quebrar acentos, e termina com "..." quando corta.
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+// renderText é a representação em texto puro do alerta, usada pelo email.
+func renderText(a Alert) string {
+	var b strings.Builder
+
+	if a.IsResolved() {
+		b.WriteString("\nDATABASE MONITORING ALERT RESOLVED\n\n")
+	} else {
+		b.WriteString("\nDATABASE MONITORING ALERT\n\n")
+	}
+
+	fmt.Fprintf(&b, "Database: %s\n", a.DatabaseName)
+	fmt.Fprintf(&b, "Alert Type: %s\n", a.AlertType)
+	fmt.Fprintf(&b, "Severity: %s\n", a.Severity)
+	fmt.Fprintf(&b, "Message: %s\n", a.Message)
+	if a.Value > 0 && a.Threshold > 0 {
+		fmt.Fprintf(&b, "Current Value: %d\n", a.Value)
+		fmt.Fprintf(&b, "Configured Threshold: %d\n", a.Threshold)
+	}
+
+	if a.IsResolved() {
+		fmt.Fprintf(&b, "Started At: %s\n", a.StartedAt.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(&b, "Resolved At: %s\n", a.Timestamp.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(&b, "Duration: %s\n", a.Timestamp.Sub(a.StartedAt).Round(time.Second))
+		b.WriteString("\nThe condition that triggered this alert is no longer present.\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "Timestamp: %s\n", a.Timestamp.Format("2006-01-02 15:04:05"))
+	b.WriteString(`
+This is an automated alert from the database monitoring system.
+Please check the database status immediately.
+
+Connection Pool Information:
+- Pool connections are managed automatically
+- Unhealthy connections are automatically recreated
+`)
+	if a.HealthCheckInterval > 0 {
+		fmt.Fprintf(&b, "- Health checks run every %d seconds\n", a.HealthCheckInterval)
+	}
+
+	return b.String()
+}
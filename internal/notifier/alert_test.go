@@ -0,0 +1,40 @@
+package notifier
+
+import (
+	"strings"
+	"testing"
+
+	"dbMonitor/internal/templates"
+)
+
+// TestShippedTemplates executa os templates de exemplo do repositório com os
+// alertas registrados por sampleAlerts, como faz o config.Load.
+func TestShippedTemplates(t *testing.T) {
+	_, err := templates.Load(map[string]map[string]templates.Files{
+		"email": {templates.DefaultKey: {
+			Subject: "../../templates/email_subject.tmpl",
+			Text:    "../../templates/email_body.tmpl",
+			HTML:    "../../templates/email_body.html",
+		}},
+		"slack": {templates.DefaultKey: {Text: "../../templates/slack_message_pt.tmpl"}},
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if _, err := templates.LoadText("../../templates/webhook_incident.json.tmpl"); err != nil {
+		t.Fatalf("LoadText: %v", err)
+	}
+}
+
+func TestRenderTextHealthCheckInterval(t *testing.T) {
+	alert := testAlert()
+	if text := renderText(alert); strings.Contains(text, "Health checks") {
+		t.Fatalf("texto cita health checks sem intervalo:\n%s", text)
+	}
+
+	alert.HealthCheckInterval = 30
+	if text := renderText(alert); !strings.Contains(text, "- Health checks run every 30 seconds\n") {
+		t.Fatalf("texto sem o intervalo dos health checks:\n%s", text)
+	}
+}
@@ -0,0 +1,334 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dbMonitor/internal/config"
+)
+
+const defaultChannelTimeout = 30 * time.Second
+
+// Channel é um canal de notificação com nome, timeout próprio e, opcionalmente,
+// um fallback usado quando a entrega falha. Também acompanha a saúde do canal
+// a partir do resultado de cada envio.
+type Channel struct {
+	name     string
+	notifier Notifier
+	timeout  time.Duration
+
+	// fallback só é usado sem fila: com a fila habilitada, é ela que
+	// encaminha ao fallback depois de esgotar as tentativas.
+	fallback     Notifier
+	fallbackName string
+
+	mu     sync.Mutex
+	health ChannelHealth
+}
+
+func NewChannel(name string, n Notifier, timeout time.Duration) *Channel {
+	if timeout <= 0 {
+		timeout = defaultChannelTimeout
+	}
+	return &Channel{
+		name:     name,
+		notifier: n,
+		timeout:  timeout,
+		health:   ChannelHealth{Channel: name, Healthy: true},
+	}
+}
+
+func (c *Channel) Name() string {
+	return c.name
+}
+
+// SendAlert tenta o canal e, em caso de falha, a cadeia de fallbacks. Só
+// retorna erro quando nenhum canal da cadeia conseguiu entregar.
+func (c *Channel) SendAlert(ctx context.Context, alert Alert) error {
+	result := c.Deliver(ctx, alert)
+	if result.DeliveredBy == "" {
+		return result
+	}
+	return nil
+}
+
+// Deliver envia o alerta seguindo a cadeia de fallbacks e registra cada
+// tentativa no resultado.
+func (c *Channel) Deliver(ctx context.Context, alert Alert) ChannelResult {
+	start := time.Now()
+	result := ChannelResult{Channel: c.name}
+
+	attempt := c.attempt(ctx, alert)
+	result.Attempts = append(result.Attempts, attempt)
+	if attempt.Error == "" {
+		result.DeliveredBy = c.name
+	} else if c.fallback != nil {
+		log.Printf("Canal %s falhou, tentando fallback %s: %s", c.name, c.fallbackName, attempt.Error)
+		fallback := deliver(ctx, alert, c.fallback, c.fallbackName)
+		result.Attempts = append(result.Attempts, fallback.Attempts...)
+		result.DeliveredBy = fallback.DeliveredBy
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// attempt chama o notifier com um contexto limitado pelo timeout do canal; o
+// envio é cancelado ao estourar, para não ser concluído depois de já contado
+// como falha.
+func (c *Channel) attempt(ctx context.Context, alert Alert) DeliveryAttempt {
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.notifier.SendAlert(ctx, alert)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timeout após %s: %w", c.timeout, err)
+	}
+
+	attempt := DeliveryAttempt{Channel: c.name, Duration: time.Since(start)}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	c.record(attempt)
+
+	return attempt
+}
+
+func (c *Channel) record(attempt DeliveryAttempt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.health.LastDuration = attempt.Duration
+	if attempt.Error == "" {
+		c.health.Sent++
+		c.health.ConsecutiveFailures = 0
+		c.health.Healthy = true
+		c.health.LastSuccessAt = &now
+		return
+	}
+
+	c.health.Failed++
+	c.health.ConsecutiveFailures++
+	c.health.Healthy = false
+	c.health.LastFailureAt = &now
+	c.health.LastError = attempt.Error
+}
+
+func (c *Channel) Health() ChannelHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	health := c.health
+	health.Fallback = c.fallbackName
+	return health
+}
+
+// ChannelHealth resume os envios recentes de um canal. Um canal fica não
+// saudável a partir da primeira falha e volta ao normal no próximo sucesso.
+type ChannelHealth struct {
+	Channel             string        `json:"channel"`
+	Healthy             bool          `json:"healthy"`
+	Fallback            string        `json:"fallback,omitempty"`
+	Sent                uint64        `json:"sent"`
+	Failed              uint64        `json:"failed"`
+	ConsecutiveFailures int           `json:"consecutive_failures"`
+	LastDuration        time.Duration `json:"last_duration_ns"`
+	LastSuccessAt       *time.Time    `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time    `json:"last_failure_at,omitempty"`
+	LastError           string        `json:"last_error,omitempty"`
+}
+
+type DeliveryAttempt struct {
+	Channel  string        `json:"channel"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// ChannelResult é o resultado da entrega em um canal, incluindo as tentativas
+// nos fallbacks. DeliveredBy fica vazio quando toda a cadeia falhou.
+type ChannelResult struct {
+	Channel     string            `json:"channel"`
+	DeliveredBy string            `json:"delivered_by,omitempty"`
+	Duration    time.Duration     `json:"duration_ns"`
+	Attempts    []DeliveryAttempt `json:"attempts"`
+}
+
+func (r ChannelResult) Failed() bool {
+	return r.DeliveredBy == ""
+}
+
+func (r ChannelResult) Error() string {
+	errs := make([]string, 0, len(r.Attempts))
+	for _, a := range r.Attempts {
+		if a.Error != "" {
+			errs = append(errs, fmt.Sprintf("%s: %s", a.Channel, a.Error))
+		}
+	}
+	return strings.Join(errs, "; ")
+}
+
+// DeliveryError é retornado pelo fan-out quando ao menos um canal falhou e
+// traz o resultado de todos os canais.
+type DeliveryError struct {
+	Results []ChannelResult
+}
+
+func (e *DeliveryError) Error() string {
+	var failed []string
+	for _, r := range e.Results {
+		if r.Failed() {
+			failed = append(failed, fmt.Sprintf("[%s]", r.Error()))
+		}
+	}
+	return fmt.Sprintf("falha em %d de %d canais: %s", len(failed), len(e.Results), strings.Join(failed, " "))
+}
+
+// ChannelSet guarda os canais configurados para que os fallbacks possam ser
+// ligados por nome e a saúde de todos seja consultada em um só lugar.
+type ChannelSet struct {
+	config   map[string]config.ChannelConfig
+	channels map[string]*Channel
+}
+
+func NewChannelSet(cfg map[string]config.ChannelConfig) *ChannelSet {
+	return &ChannelSet{
+		config:   cfg,
+		channels: make(map[string]*Channel),
+	}
+}
+
+// Add registra o notifier como canal, com o timeout configurado para o nome.
+func (s *ChannelSet) Add(name string, n Notifier) *Channel {
+	c := NewChannel(name, n, time.Duration(s.config[name].Timeout)*time.Second)
+	s.channels[name] = c
+	return c
+}
+
+func (s *ChannelSet) Get(name string) (*Channel, bool) {
+	c, ok := s.channels[name]
+	return c, ok
+}
+
+// Names retorna os nomes dos canais registrados, em ordem alfabética.
+func (s *ChannelSet) Names() []string {
+	names := make([]string, 0, len(s.channels))
+	for name := range s.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fallbackTarget é implementado pelas entregas que podem encaminhar a um
+// fallback: o Channel, que tenta o fallback logo após a falha, e a fila, que
+// só o usa depois de esgotar as tentativas.
+type fallbackTarget interface {
+	setFallback(name string, n Notifier)
+}
+
+func (c *Channel) setFallback(name string, n Notifier) {
+	c.fallback = n
+	c.fallbackName = name
+}
+
+// LinkFallbacks liga a entrega de cada canal (o Channel ou a fila dele) à
+// entrega do fallback configurado, para que o fallback também passe pela fila
+// persistente. Um fallback que não foi inicializado (ex.: Slack sem webhook) é
+// ignorado com um aviso.
+func (s *ChannelSet) LinkFallbacks(deliveries map[string]Notifier) {
+	for name, c := range s.channels {
+		fallback := s.config[name].Fallback
+		if fallback == "" {
+			continue
+		}
+		target, ok := deliveries[fallback]
+		if !ok {
+			log.Printf("Warning: fallback %s do canal %s não está ativo", fallback, name)
+			continue
+		}
+
+		c.fallbackName = fallback
+		if source, ok := deliveries[name].(fallbackTarget); ok {
+			source.setFallback(fallback, target)
+		}
+	}
+}
+
+func (s *ChannelSet) Health() []ChannelHealth {
+	health := make([]ChannelHealth, 0, len(s.channels))
+	for _, c := range s.channels {
+		health = append(health, c.Health())
+	}
+	sort.Slice(health, func(i, j int) bool {
+		return health[i].Channel < health[j].Channel
+	})
+	return health
+}
+
+// namedNotifier é implementado pelos notifiers que sabem o nome do seu canal.
+type namedNotifier interface {
+	Name() string
+}
+
+func notifierName(n Notifier, index int) string {
+	if named, ok := n.(namedNotifier); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T#%d", n, index)
+}
+
+// fanOut envia o alerta a todos os notifiers em paralelo.
+func fanOut(ctx context.Context, alert Alert, notifiers []Notifier) []ChannelResult {
+	results := make([]ChannelResult, len(notifiers))
+
+	var wg sync.WaitGroup
+	for i, n := range notifiers {
+		wg.Add(1)
+		go func(i int, n Notifier) {
+			defer wg.Done()
+			results[i] = deliver(ctx, alert, n, notifierName(n, i))
+		}(i, n)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// deliver envia o alerta a um notifier. Quando ele é um Channel, o resultado
+// traz as tentativas de fallback; os demais viram um resultado com uma única
+// tentativa.
+func deliver(ctx context.Context, alert Alert, n Notifier, name string) ChannelResult {
+	if c, ok := n.(*Channel); ok {
+		return c.Deliver(ctx, alert)
+	}
+
+	start := time.Now()
+	err := n.SendAlert(ctx, alert)
+	attempt := DeliveryAttempt{Channel: name, Duration: time.Since(start)}
+	result := ChannelResult{Channel: name, Duration: attempt.Duration}
+	if err != nil {
+		attempt.Error = err.Error()
+	} else {
+		result.DeliveredBy = name
+	}
+	result.Attempts = []DeliveryAttempt{attempt}
+
+	return result
+}
+
+func deliveryError(results []ChannelResult) error {
+	for _, r := range results {
+		if r.Failed() {
+			return &DeliveryError{Results: results}
+		}
+	}
+	return nil
+}
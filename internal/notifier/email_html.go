@@ -0,0 +1,194 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"image/color"
+	"strings"
+
+	"dbMonitor/internal/database"
+	"dbMonitor/internal/templates"
+)
+
+const sparklineCID = "sparkline.png"
+
+var severityColors = map[Severity]color.RGBA{
+	SeverityWarning:  {R: 249, G: 168, B: 37, A: 255},
+	SeverityCritical: {R: 211, G: 47, B: 47, A: 255},
+}
+
+var resolvedColor = color.RGBA{R: 46, G: 125, B: 50, A: 255}
+
+const defaultEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; color: #212121;">
+  <div style="background-color: {{.BannerColor}}; color: #ffffff; padding: 12px 16px; font-size: 18px; font-weight: bold;">
+    {{.Title}}
+  </div>
+  <p><strong>{{.Alert.DatabaseName}}</strong> ({{.Alert.DatabaseType}}) &middot; {{.Alert.AlertType}} &middot; {{formatTime .Alert.Timestamp "2006-01-02 15:04:05"}}</p>
+  <pre style="background-color: #f5f5f5; padding: 8px;">{{.Alert.Message}}</pre>
+  {{if and .Alert.Value .Alert.Threshold}}<p>Current value <strong>{{.Alert.Value}}</strong> against threshold <strong>{{.Alert.Threshold}}</strong>.</p>{{end}}
+  {{if .Alert.IsResolved}}<p>Resolved after {{humanizeDuration (.Alert.Timestamp.Sub .Alert.StartedAt)}}.</p>{{else}}<p>Firing since {{formatTime .Alert.StartedAt "2006-01-02 15:04:05"}} ({{humanizeDuration (.Alert.Timestamp.Sub .Alert.StartedAt)}}).</p>{{end}}
+  {{if .Rows}}
+  <table cellpadding="6" cellspacing="0" style="border-collapse: collapse; border: 1px solid #e0e0e0;">
+    <tr style="background-color: #eeeeee;"><th align="left">Metric</th><th align="right">Current</th><th align="right">Warning</th><th align="right">Critical</th></tr>
+    {{range .Rows}}
+    <tr>
+      <td>{{.Name}}</td>
+      <td align="right" style="color: {{.Color}}; font-weight: bold;">{{.Value}}</td>
+      <td align="right">{{if .Warning}}{{.Warning}}{{else}}-{{end}}</td>
+      <td align="right">{{if .Critical}}{{.Critical}}{{else}}-{{end}}</td>
+    </tr>
+    {{end}}
+  </table>
+  {{end}}
+  {{if .HasSparkline}}
+  <p>{{.SparklineMetric}} over the last {{.SparklineSamples}} checks:</p>
+  <img src="cid:{{.SparklineCID}}" width="320" height="80" alt="{{.SparklineMetric}} history">
+  {{end}}
+  <p style="color: #757575; font-size: 12px;">This is an automated alert from the database monitoring system.</p>
+</body>
+</html>
+`
+
+var defaultEmailHTMLTemplate = htmltemplate.Must(htmltemplate.New("email").Funcs(templates.FuncMap()).Parse(defaultEmailHTML))
+
+type statRow struct {
+	Name     string
+	Value    int
+	Warning  int
+	Critical int
+	Color    string
+}
+
+type emailHTMLData struct {
+	Alert            Alert
+	Title            string
+	BannerColor      string
+	Rows             []statRow
+	HasSparkline     bool
+	SparklineCID     string
+	SparklineMetric  string
+	SparklineSamples int
+}
+
+// sparklineSeries escolhe a métrica do histórico relacionada ao tipo do
+// alerta. Alertas sem métrica própria usam o total de conexões.
+func sparklineSeries(alert Alert) (string, string, []int) {
+	metric, name := "total", "Total connections"
+	switch alert.AlertType {
+	case "HIGH_ACTIVE_CONNECTIONS":
+		metric, name = "active", "Active connections"
+	case "HIGH_INACTIVE_CONNECTIONS":
+		metric, name = "inactive", "Inactive connections"
+	}
+
+	values := make([]int, 0, len(alert.History))
+	for _, stats := range alert.History {
+		values = append(values, statValue(stats, metric))
+	}
+	return metric, name, values
+}
+
+func statValue(stats database.SessionStats, metric string) int {
+	switch metric {
+	case "active":
+		return stats.Active
+	case "inactive":
+		return stats.Inactive
+	default:
+		return stats.Total
+	}
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// renderSparklinePNG retorna nil quando não há histórico suficiente.
+func renderSparklinePNG(alert Alert) ([]byte, error) {
+	metric, _, values := sparklineSeries(alert)
+	if len(values) < 2 {
+		return nil, nil
+	}
+
+	lineColor, ok := severityColors[alert.Severity]
+	if alert.IsResolved() || !ok {
+		lineColor = resolvedColor
+	}
+
+	levels := alert.Thresholds[metric]
+	threshold := levels.Critical
+	if alert.Severity == SeverityWarning && levels.Warning > 0 {
+		threshold = levels.Warning
+	}
+
+	return renderSparkline(values, threshold, lineColor)
+}
+
+// renderDefaultHTML é a parte HTML usada quando não há template html
+// configurado para o email.
+func renderDefaultHTML(alert Alert, hasSparkline bool) (string, error) {
+	bannerColor, ok := severityColors[alert.Severity]
+	if !ok {
+		bannerColor = severityColors[SeverityCritical]
+	}
+	title := fmt.Sprintf("%s ALERT: %s - %s", strings.ToUpper(string(alert.Severity)), alert.DatabaseName, alert.AlertType)
+	if alert.IsResolved() {
+		bannerColor = resolvedColor
+		title = fmt.Sprintf("RESOLVED: %s - %s", alert.DatabaseName, alert.AlertType)
+	}
+
+	data := emailHTMLData{
+		Alert:        alert,
+		Title:        title,
+		BannerColor:  hexColor(bannerColor),
+		HasSparkline: hasSparkline,
+		SparklineCID: sparklineCID,
+	}
+
+	if hasSparkline {
+		_, data.SparklineMetric, _ = sparklineSeries(alert)
+		data.SparklineSamples = len(alert.History)
+	}
+
+	if stats := alert.Stats; stats != nil {
+		rows := []struct {
+			name   string
+			metric string
+			value  int
+		}{
+			{"Active", "active", stats.Active},
+			{"Inactive", "inactive", stats.Inactive},
+			{"Idle", "", stats.Idle},
+			{"Idle in transaction", "", stats.IdleInTxn},
+			{"Waiting", "", stats.Waiting},
+			{"Total", "total", stats.Total},
+			{"Max connections", "", stats.MaxConnections},
+		}
+
+		for _, r := range rows {
+			levels := alert.Thresholds[r.metric]
+			rowColor := "#212121"
+			if levels.Critical > 0 && r.value > levels.Critical {
+				rowColor = hexColor(severityColors[SeverityCritical])
+			} else if levels.Warning > 0 && r.value > levels.Warning {
+				rowColor = hexColor(severityColors[SeverityWarning])
+			}
+			data.Rows = append(data.Rows, statRow{
+				Name:     r.name,
+				Value:    r.value,
+				Warning:  levels.Warning,
+				Critical: levels.Critical,
+				Color:    rowColor,
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := defaultEmailHTMLTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("falha ao renderizar HTML do email: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -2,36 +2,76 @@ package notifier
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"time"
 
 	"dbMonitor/internal/config"
+	"dbMonitor/internal/templates"
 	"gopkg.in/gomail.v2"
 )
 
+// Notifier entrega um alerta. O envio deve respeitar o cancelamento e o prazo
+// do contexto, para que um timeout não deixe a entrega correndo por trás.
 type Notifier interface {
-	SendAlert(subject, body string) error
+	SendAlert(ctx context.Context, alert Alert) error
 }
 
 type EmailNotifier struct {
-	config config.EmailConfig
-	dialer *gomail.Dialer
+	config    config.EmailConfig
+	dialer    *smtpDialer
+	templates *templates.Set
 }
 
-func NewEmailNotifier(cfg config.EmailConfig) *EmailNotifier {
-	d := gomail.NewDialer(cfg.SMTPHost, cfg.SMTPPort, cfg.Username, cfg.Password)
-	d.TLSConfig = nil
+func NewEmailNotifier(cfg config.EmailConfig, tmpl *templates.Set) (*EmailNotifier, error) {
+	d, err := newSMTPDialer(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	return &EmailNotifier{
-		config: cfg,
-		dialer: d,
-	}
+		config:    cfg,
+		dialer:    d,
+		templates: tmpl,
+	}, nil
 }
 
-func (e *EmailNotifier) SendAlert(subject, body string) error {
+func (e *EmailNotifier) SendAlert(ctx context.Context, alert Alert) error {
+	subject, ok, err := e.templates.RenderSubject("email", alert.AlertType, alert)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		subject = alert.Subject()
+	}
+
+	body, ok, err := e.templates.RenderText("email", alert.AlertType, alert)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		body = renderText(alert)
+	}
+
+	sparkline, err := renderSparklinePNG(alert)
+	if err != nil {
+		log.Printf("Falha ao gerar gráfico do alerta %s: %v", subject, err)
+	}
+
+	htmlBody, ok, err := e.templates.RenderHTML("email", alert.AlertType, alert)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if htmlBody, err = renderDefaultHTML(alert, sparkline != nil); err != nil {
+			return err
+		}
+	}
+
 	m := gomail.NewMessage()
 
 	m.SetHeader("From", e.config.FromEmail)
@@ -41,8 +81,15 @@ func (e *EmailNotifier) SendAlert(subject, body string) error {
 	m.SetHeader("To", e.config.ToEmails...)
 	m.SetHeader("Subject", subject)
 	m.SetBody("text/plain", body)
+	m.AddAlternative("text/html", htmlBody)
+	if sparkline != nil {
+		m.Embed(sparklineCID, gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(sparkline)
+			return err
+		}))
+	}
 
-	if err := e.dialer.DialAndSend(m); err != nil {
+	if err := e.dialer.DialAndSend(ctx, m); err != nil {
 		return fmt.Errorf("falha ao enviar email: %w", err)
 	}
 
@@ -51,7 +98,7 @@ func (e *EmailNotifier) SendAlert(subject, body string) error {
 }
 
 func (e *EmailNotifier) TestConnection() error {
-	closer, err := e.dialer.Dial()
+	closer, err := e.dialer.Dial(context.Background())
 	if err != nil {
 		return fmt.Errorf("erro ao conectar com servidor SMTP: %w", err)
 	}
@@ -62,24 +109,131 @@ func (e *EmailNotifier) TestConnection() error {
 // Slack Notifier
 type SlackNotifier struct {
 	webhookURL string
+	templates  *templates.Set
+
+	// Modo Web API (bot token): mensagens em Block Kit, com repetições e
+	// resolução em thread da mensagem original.
+	api *slackAPI
 }
 
-func NewSlackNotifier(cfg config.SlackConfig) (*SlackNotifier, error) {
+func NewSlackNotifier(cfg config.SlackConfig, tmpl *templates.Set) (*SlackNotifier, error) {
+	if cfg.BotToken != "" {
+		if cfg.Channel == "" {
+			return nil, fmt.Errorf("canal do Slack não configurado para o bot token")
+		}
+		return &SlackNotifier{
+			templates: tmpl,
+			api:       newSlackAPI(cfg),
+		}, nil
+	}
+
 	if cfg.WebhookURL == "" {
 		return nil, fmt.Errorf("URL do webhook do Slack não configurada")
 	}
 	return &SlackNotifier{
 		webhookURL: cfg.WebhookURL,
+		templates:  tmpl,
 	}, nil
 }
 
-func (s *SlackNotifier) SendAlert(subject, body string) error {
-	payload := map[string]string{
-		"text": fmt.Sprintf("*%s*\n```%s```", subject, body),
+var slackSeverityColors = map[Severity]string{
+	SeverityWarning:  "warning",
+	SeverityCritical: "danger",
+}
+
+// renderTitleAndText aplica os templates do canal slack, quando houver.
+func (s *SlackNotifier) renderTitleAndText(alert Alert) (string, string, bool, error) {
+	title, titleOK, err := s.templates.RenderSubject("slack", alert.AlertType, alert)
+	if err != nil {
+		return "", "", false, err
+	}
+	if !titleOK {
+		title = alert.Subject()
 	}
-	jsonPayload, _ := json.Marshal(payload)
 
-	req, err := http.NewRequest("POST", s.webhookURL, bytes.NewBuffer(jsonPayload))
+	text, textOK, err := s.templates.RenderText("slack", alert.AlertType, alert)
+	if err != nil {
+		return "", "", false, err
+	}
+	if !textOK {
+		text = alert.Message
+	}
+
+	return title, text, titleOK, nil
+}
+
+func (s *SlackNotifier) renderPayload(alert Alert) (map[string]interface{}, error) {
+	payload := renderSlackPayload(alert)
+
+	title, text, titleOK, err := s.renderTitleAndText(alert)
+	if err != nil {
+		return nil, err
+	}
+	if titleOK {
+		payload["text"] = title
+	}
+	payload["attachments"].([]map[string]interface{})[0]["text"] = text
+
+	return payload, nil
+}
+
+func renderSlackPayload(alert Alert) map[string]interface{} {
+	color := slackSeverityColors[alert.Severity]
+	if alert.IsResolved() {
+		color = "good"
+	}
+
+	return map[string]interface{}{
+		"text": fmt.Sprintf("*%s*", alert.Subject()),
+		"attachments": []map[string]interface{}{
+			{
+				"color":  color,
+				"text":   alert.Message,
+				"fields": slackFields(alert),
+				"ts":     alert.Timestamp.Unix(),
+			},
+		},
+	}
+}
+
+func slackFields(alert Alert) []map[string]interface{} {
+	fields := []map[string]interface{}{
+		{"title": "Database", "value": alert.DatabaseName, "short": true},
+		{"title": "Alert Type", "value": alert.AlertType, "short": true},
+		{"title": "Severity", "value": string(alert.Severity), "short": true},
+		{"title": "State", "value": string(alert.State), "short": true},
+	}
+	if alert.Value > 0 && alert.Threshold > 0 {
+		fields = append(fields,
+			map[string]interface{}{"title": "Current Value", "value": fmt.Sprintf("%d", alert.Value), "short": true},
+			map[string]interface{}{"title": "Threshold", "value": fmt.Sprintf("%d", alert.Threshold), "short": true},
+		)
+	}
+	return fields
+}
+
+func (s *SlackNotifier) SendAlert(ctx context.Context, alert Alert) error {
+	if s.api != nil {
+		title, text, _, err := s.renderTitleAndText(alert)
+		if err != nil {
+			return err
+		}
+		return s.api.send(ctx, alert, title, text)
+	}
+
+	subject := alert.Subject()
+
+	payload, err := s.renderPayload(alert)
+	if err != nil {
+		return err
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("falha ao montar payload do Slack: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.webhookURL, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("falha ao criar requisição para o Slack: %w", err)
 	}
@@ -111,56 +265,95 @@ func NewMultiNotifier(notifiers ...Notifier) *MultiNotifier {
 	}
 }
 
-func (m *MultiNotifier) SendAlert(subject, body string) error {
-	var errors []error
-	for _, notifier := range m.notifiers {
-		if err := notifier.SendAlert(subject, body); err != nil {
-			errors = append(errors, err)
+// SendAlert envia para todos os notifiers em paralelo. Em caso de falha, o
+// erro é um *DeliveryError com o resultado de cada canal.
+func (m *MultiNotifier) SendAlert(ctx context.Context, alert Alert) error {
+	return deliveryError(m.Deliver(ctx, alert))
+}
+
+func (m *MultiNotifier) Deliver(ctx context.Context, alert Alert) []ChannelResult {
+	return fanOut(ctx, alert, m.notifiers)
+}
+
+// SeverityRouter
+type SeverityRouter struct {
+	routes []severityRoute
+}
+
+type severityRoute struct {
+	notifier   Notifier
+	severities map[string]bool
+}
+
+func (r severityRoute) accepts(alert Alert) bool {
+	if r.severities == nil {
+		return true
+	}
+	for _, severity := range alert.routingSeverities() {
+		if r.severities[string(severity)] {
+			return true
+		}
+	}
+	return false
+}
+
+func NewSeverityRouter() *SeverityRouter {
+	return &SeverityRouter{}
+}
+
+// Route adiciona um notifier que recebe apenas as severidades informadas.
+// Sem severidades, o notifier recebe todos os alertas.
+func (r *SeverityRouter) Route(notifier Notifier, severities ...string) {
+	route := severityRoute{notifier: notifier}
+	if len(severities) > 0 {
+		route.severities = make(map[string]bool)
+		for _, severity := range severities {
+			route.severities[severity] = true
 		}
 	}
+	r.routes = append(r.routes, route)
+}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("múltiplos erros de notificação: %v", errors)
+// SendAlert encaminha o alerta, em paralelo, apenas aos notifiers que aceitam
+// a severidade; a resolução vai aos que aceitam alguma das severidades
+// notificadas. Em caso de falha, o erro é um *DeliveryError.
+func (r *SeverityRouter) SendAlert(ctx context.Context, alert Alert) error {
+	return deliveryError(r.Deliver(ctx, alert))
+}
+
+func (r *SeverityRouter) Deliver(ctx context.Context, alert Alert) []ChannelResult {
+	var targets []Notifier
+	for _, route := range r.routes {
+		if route.accepts(alert) {
+			targets = append(targets, route.notifier)
+		}
 	}
 
-	return nil
+	return fanOut(ctx, alert, targets)
 }
 
 // Mock Notifier para testes
 type MockNotifier struct {
-	SentAlerts []struct {
-		Subject string
-		Body    string
-	}
+	SentAlerts []Alert
 }
 
 func NewMockNotifier() *MockNotifier {
 	return &MockNotifier{
-		SentAlerts: make([]struct {
-			Subject string
-			Body    string
-		}, 0),
-	}
-}
-
-func (m *MockNotifier) SendAlert(subject, body string) error {
-	m.SentAlerts = append(m.SentAlerts, struct {
-		Subject string
-		Body    string
-	}{
-		Subject: subject,
-		Body:    body,
-	})
-	log.Printf("Mock alert: %s", subject)
+		SentAlerts: make([]Alert, 0),
+	}
+}
+
+func (m *MockNotifier) SendAlert(ctx context.Context, alert Alert) error {
+	m.SentAlerts = append(m.SentAlerts, alert)
+	log.Printf("Mock alert: %s", alert.Subject())
 	return nil
 }
 
-func (m *MockNotifier) GetLastAlert() (string, string) {
+func (m *MockNotifier) GetLastAlert() (Alert, bool) {
 	if len(m.SentAlerts) == 0 {
-		return "", ""
+		return Alert{}, false
 	}
-	last := m.SentAlerts[len(m.SentAlerts)-1]
-	return last.Subject, last.Body
+	return m.SentAlerts[len(m.SentAlerts)-1], true
 }
 
 func (m *MockNotifier) GetAlertCount() int {
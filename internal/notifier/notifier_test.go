@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testAlert é o alerta usado pelos testes dos canais.
+func testAlert() Alert {
+	return Alert{
+		DatabaseName: "postgres_producao",
+		DatabaseType: "postgresql",
+		AlertType:    "HIGH_TOTAL_CONNECTIONS",
+		Severity:     SeverityCritical,
+		State:        AlertStateFiring,
+		Message:      "High total number of connections detected",
+		Value:        160,
+		Threshold:    150,
+		Timestamp:    time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+type capturedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// testServer responde a toda requisição com o status e o corpo informados e
+// entrega cada requisição recebida no canal, na ordem de chegada.
+func testServer(t *testing.T, status int, response string) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+
+	requests := make(chan capturedRequest, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- capturedRequest{method: r.Method, path: r.URL.Path, header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
@@ -0,0 +1,156 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"dbMonitor/internal/config"
+)
+
+const defaultPagerDutyURL = "https://events.pagerduty.com"
+
+// PagerDutyNotifier abre e resolve incidentes pela Events API v2. A dedup key
+// é derivada da base e do tipo do alerta, de modo que repetições atualizam o
+// mesmo incidente e a recuperação o resolve.
+type PagerDutyNotifier struct {
+	routingKey string
+	eventsURL  string
+	client     *http.Client
+}
+
+func NewPagerDutyNotifier(cfg config.PagerDutyConfig) (*PagerDutyNotifier, error) {
+	if cfg.RoutingKey == "" {
+		return nil, fmt.Errorf("routing key do PagerDuty não configurada")
+	}
+
+	baseURL := cfg.APIURL
+	if baseURL == "" {
+		baseURL = defaultPagerDutyURL
+	}
+
+	return &PagerDutyNotifier{
+		routingKey: cfg.RoutingKey,
+		eventsURL:  strings.TrimRight(baseURL, "/") + "/v2/enqueue",
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp"`
+	Component     string                 `json:"component,omitempty"`
+	Class         string                 `json:"class"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+type pagerDutyResponse struct {
+	Status  string   `json:"status"`
+	Message string   `json:"message"`
+	Errors  []string `json:"errors"`
+}
+
+var pagerDutySeverities = map[Severity]string{
+	SeverityWarning:  "warning",
+	SeverityCritical: "critical",
+}
+
+func pagerDutyDedupKey(alert Alert) string {
+	return fmt.Sprintf("dbmonitor/%s/%s", alert.DatabaseName, alert.AlertType)
+}
+
+func renderPagerDutyEvent(routingKey string, alert Alert) pagerDutyEvent {
+	event := pagerDutyEvent{
+		RoutingKey: routingKey,
+		DedupKey:   pagerDutyDedupKey(alert),
+	}
+
+	if alert.IsResolved() {
+		event.EventAction = "resolve"
+		return event
+	}
+
+	severity, ok := pagerDutySeverities[alert.Severity]
+	if !ok {
+		severity = "error"
+	}
+
+	details := map[string]interface{}{
+		"message": alert.Message,
+	}
+	if alert.Value > 0 && alert.Threshold > 0 {
+		details["value"] = alert.Value
+		details["threshold"] = alert.Threshold
+	}
+	if len(alert.Labels) > 0 {
+		details["labels"] = alert.Labels
+	}
+	if alert.Stats != nil {
+		details["session_stats"] = alert.Stats
+	}
+
+	event.EventAction = "trigger"
+	event.Payload = &pagerDutyPayload{
+		Summary:       fmt.Sprintf("%s: %s", alert.Subject(), alert.Message),
+		Source:        alert.DatabaseName,
+		Severity:      severity,
+		Timestamp:     alert.Timestamp.Format(time.RFC3339),
+		Component:     alert.DatabaseType,
+		Class:         alert.AlertType,
+		CustomDetails: details,
+	}
+
+	// O PagerDuty limita o summary a 1024 caracteres.
+	event.Payload.Summary = truncate(event.Payload.Summary, 1024)
+
+	return event
+}
+
+func (p *PagerDutyNotifier) SendAlert(ctx context.Context, alert Alert) error {
+	event := renderPagerDutyEvent(p.routingKey, alert)
+
+	jsonPayload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("falha ao montar evento do PagerDuty: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.eventsURL, bytes.NewReader(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("falha ao criar requisição para o PagerDuty: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("falha ao enviar evento para o PagerDuty: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode != http.StatusAccepted {
+		var pdResp pagerDutyResponse
+		if json.Unmarshal(body, &pdResp) == nil && pdResp.Message != "" {
+			return fmt.Errorf("erro do PagerDuty: %s: %s %v", resp.Status, pdResp.Message, pdResp.Errors)
+		}
+		return fmt.Errorf("erro do PagerDuty: %s", resp.Status)
+	}
+
+	log.Printf("Evento do PagerDuty enviado com sucesso (%s): %s", event.EventAction, event.DedupKey)
+	return nil
+}
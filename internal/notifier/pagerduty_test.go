@@ -0,0 +1,125 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"dbMonitor/internal/config"
+)
+
+func TestPagerDutyEvents(t *testing.T) {
+	resolved := testAlert()
+	resolved.State = AlertStateResolved
+
+	warning := testAlert()
+	warning.Severity = SeverityWarning
+
+	tests := []struct {
+		name         string
+		alert        Alert
+		wantAction   string
+		wantSeverity string
+	}{
+		{name: "critical abre incidente", alert: testAlert(), wantAction: "trigger", wantSeverity: "critical"},
+		{name: "warning abre incidente", alert: warning, wantAction: "trigger", wantSeverity: "warning"},
+		{name: "resolução fecha incidente", alert: resolved, wantAction: "resolve"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := testServer(t, http.StatusAccepted, `{"status":"success","message":"Event processed"}`)
+
+			p, err := NewPagerDutyNotifier(config.PagerDutyConfig{RoutingKey: "chave", APIURL: server.URL + "/"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := p.SendAlert(context.Background(), tt.alert); err != nil {
+				t.Fatalf("SendAlert: %v", err)
+			}
+
+			req := <-requests
+			if req.path != "/v2/enqueue" {
+				t.Errorf("path = %s, esperado /v2/enqueue", req.path)
+			}
+			var event pagerDutyEvent
+			if err := json.Unmarshal(req.body, &event); err != nil {
+				t.Fatalf("evento inválido: %v", err)
+			}
+			if event.RoutingKey != "chave" {
+				t.Errorf("routing_key = %q", event.RoutingKey)
+			}
+			if event.EventAction != tt.wantAction {
+				t.Errorf("event_action = %q, esperado %q", event.EventAction, tt.wantAction)
+			}
+			// Disparo e resolução precisam da mesma dedup key para que o
+			// PagerDuty feche o incidente aberto.
+			if want := "dbmonitor/postgres_producao/HIGH_TOTAL_CONNECTIONS"; event.DedupKey != want {
+				t.Errorf("dedup_key = %q, esperado %q", event.DedupKey, want)
+			}
+
+			if tt.wantAction == "resolve" {
+				if event.Payload != nil {
+					t.Errorf("resolve não deve ter payload: %+v", event.Payload)
+				}
+				return
+			}
+			if event.Payload == nil {
+				t.Fatal("trigger sem payload")
+			}
+			if event.Payload.Severity != tt.wantSeverity {
+				t.Errorf("severity = %q, esperado %q", event.Payload.Severity, tt.wantSeverity)
+			}
+			if event.Payload.Source != "postgres_producao" || event.Payload.Class != "HIGH_TOTAL_CONNECTIONS" {
+				t.Errorf("source/class = %q/%q", event.Payload.Source, event.Payload.Class)
+			}
+		})
+	}
+}
+
+func TestPagerDutyError(t *testing.T) {
+	server, _ := testServer(t, http.StatusBadRequest, `{"status":"invalid event","message":"Event object is invalid","errors":["Length of 'routing_key' is incorrect"]}`)
+
+	p, err := NewPagerDutyNotifier(config.PagerDutyConfig{RoutingKey: "chave", APIURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = p.SendAlert(context.Background(), testAlert())
+	if err == nil || !strings.Contains(err.Error(), "Event object is invalid") {
+		t.Fatalf("erro = %v, esperado a mensagem do PagerDuty", err)
+	}
+}
+
+func TestPagerDutySummaryTruncation(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{name: "ascii", message: strings.Repeat("a", 2000)},
+		{name: "acentos", message: strings.Repeat("conexões ", 300)},
+		{name: "multibyte no limite", message: strings.Repeat("ç", 1100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := testAlert()
+			alert.Message = tt.message
+
+			summary := renderPagerDutyEvent("chave", alert).Payload.Summary
+			if !utf8.ValidString(summary) {
+				t.Fatal("summary com UTF-8 inválido")
+			}
+			if n := utf8.RuneCountInString(summary); n != 1024 {
+				t.Fatalf("summary com %d caracteres, esperado 1024", n)
+			}
+			if !strings.HasSuffix(summary, "...") {
+				t.Fatal("summary truncado sem reticências")
+			}
+		})
+	}
+}
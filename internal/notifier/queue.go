@@ -0,0 +1,452 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dbMonitor/internal/config"
+)
+
+const (
+	defaultQueueSpoolDir       = "spool"
+	defaultQueueMaxAttempts    = 8
+	defaultQueueBackoffInitial = 5 * time.Second
+	defaultQueueBackoffMax     = 10 * time.Minute
+	deadLetterDirName          = "dead"
+)
+
+// QueueManager cria e acompanha as filas de entrega de cada canal.
+type QueueManager struct {
+	spoolDir       string
+	maxAttempts    int
+	backoffInitial time.Duration
+	backoffMax     time.Duration
+
+	mu     sync.Mutex
+	queues []*QueuedNotifier
+}
+
+func NewQueueManager(cfg config.NotificationQueueConfig) *QueueManager {
+	m := &QueueManager{
+		spoolDir:       cfg.SpoolDir,
+		maxAttempts:    cfg.MaxAttempts,
+		backoffInitial: time.Duration(cfg.BackoffInitial) * time.Second,
+		backoffMax:     time.Duration(cfg.BackoffMax) * time.Second,
+	}
+
+	if m.spoolDir == "" {
+		m.spoolDir = defaultQueueSpoolDir
+	}
+	if m.maxAttempts <= 0 {
+		m.maxAttempts = defaultQueueMaxAttempts
+	}
+	if m.backoffInitial <= 0 {
+		m.backoffInitial = defaultQueueBackoffInitial
+	}
+	if m.backoffMax <= 0 {
+		m.backoffMax = defaultQueueBackoffMax
+	}
+
+	return m
+}
+
+// Wrap coloca o notifier atrás de uma fila persistente identificada pelo
+// nome do canal. Alertas que ficaram no spool de uma execução anterior são
+// recarregados e reenviados.
+func (m *QueueManager) Wrap(channel string, n Notifier) (*QueuedNotifier, error) {
+	q := &QueuedNotifier{
+		channel:        channel,
+		notifier:       n,
+		dir:            filepath.Join(m.spoolDir, channel),
+		maxAttempts:    m.maxAttempts,
+		backoffInitial: m.backoffInitial,
+		backoffMax:     m.backoffMax,
+		wake:           make(chan struct{}, 1),
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+
+	if err := os.MkdirAll(filepath.Join(q.dir, deadLetterDirName), 0o755); err != nil {
+		return nil, fmt.Errorf("falha ao criar spool do canal %s: %w", channel, err)
+	}
+
+	if err := q.loadSpool(); err != nil {
+		return nil, err
+	}
+
+	go q.run()
+
+	m.mu.Lock()
+	m.queues = append(m.queues, q)
+	m.mu.Unlock()
+
+	return q, nil
+}
+
+func (m *QueueManager) Stats() []QueueStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]QueueStats, 0, len(m.queues))
+	for _, q := range m.queues {
+		stats = append(stats, q.Stats())
+	}
+	return stats
+}
+
+// Close para os workers. O que não foi entregue continua no spool.
+func (m *QueueManager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, q := range m.queues {
+		q.Close()
+	}
+}
+
+type QueueStats struct {
+	Channel       string     `json:"channel"`
+	Depth         int        `json:"depth"`
+	Delivered     uint64     `json:"delivered"`
+	Failures      uint64     `json:"failures"`
+	DeadLettered  uint64     `json:"dead_lettered"`
+	FallenBack    uint64     `json:"fallen_back"`
+	DeadLetters   int        `json:"dead_letters"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+}
+
+// queuedAlert é o registro gravado no spool. History não é serializado, então
+// alertas recarregados após um restart são enviados sem o gráfico.
+type queuedAlert struct {
+	ID          string    `json:"id"`
+	Alert       Alert     `json:"alert"`
+	Attempts    int       `json:"attempts"`
+	EnqueuedAt  time.Time `json:"enqueued_at"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// QueuedNotifier entrega os alertas de um canal em ordem, com retentativas
+// em backoff exponencial. Após maxAttempts falhas o alerta segue para o
+// fallback do canal, quando houver, ou para o diretório de dead-letter.
+type QueuedNotifier struct {
+	channel        string
+	notifier       Notifier
+	dir            string
+	maxAttempts    int
+	backoffInitial time.Duration
+	backoffMax     time.Duration
+
+	fallback     Notifier
+	fallbackName string
+
+	mu           sync.Mutex
+	pending      []*queuedAlert
+	seq          uint64
+	delivered    uint64
+	failures     uint64
+	deadLettered uint64
+	fallenBack   uint64
+	lastError    string
+	lastErrorAt  time.Time
+
+	// ctx é cancelado no Close, interrompendo a entrega em andamento.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (q *QueuedNotifier) Name() string {
+	return q.channel
+}
+
+func (q *QueuedNotifier) setFallback(name string, n Notifier) {
+	q.fallback = n
+	q.fallbackName = name
+}
+
+// SendAlert grava o alerta no spool e retorna; a entrega é assíncrona.
+func (q *QueuedNotifier) SendAlert(ctx context.Context, alert Alert) error {
+	now := time.Now()
+
+	q.mu.Lock()
+	q.seq++
+	item := &queuedAlert{
+		ID:          fmt.Sprintf("%d-%06d", now.UnixNano(), q.seq),
+		Alert:       alert,
+		EnqueuedAt:  now,
+		NextAttempt: now,
+	}
+	q.mu.Unlock()
+
+	if err := q.persist(item); err != nil {
+		return fmt.Errorf("falha ao gravar alerta no spool do canal %s: %w", q.channel, err)
+	}
+
+	q.mu.Lock()
+	q.pending = append(q.pending, item)
+	q.mu.Unlock()
+
+	q.signal()
+	return nil
+}
+
+func (q *QueuedNotifier) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *QueuedNotifier) run() {
+	defer close(q.done)
+
+	for {
+		q.mu.Lock()
+		var head *queuedAlert
+		if len(q.pending) > 0 {
+			head = q.pending[0]
+		}
+		q.mu.Unlock()
+
+		if head == nil {
+			select {
+			case <-q.wake:
+				continue
+			case <-q.stop:
+				return
+			}
+		}
+
+		if wait := time.Until(head.NextAttempt); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-q.stop:
+				timer.Stop()
+				return
+			}
+		}
+
+		q.deliver(head)
+
+		select {
+		case <-q.stop:
+			return
+		default:
+		}
+	}
+}
+
+func (q *QueuedNotifier) deliver(item *queuedAlert) {
+	err := q.notifier.SendAlert(q.ctx, item.Alert)
+	if q.ctx.Err() != nil {
+		// Interrompido pelo Close: o alerta continua no spool, sem contar
+		// como tentativa.
+		return
+	}
+
+	if err == nil {
+		q.remove(item)
+		q.mu.Lock()
+		q.pending = q.pending[1:]
+		q.delivered++
+		q.mu.Unlock()
+		return
+	}
+
+	q.mu.Lock()
+	item.Attempts++
+	item.LastError = err.Error()
+	q.failures++
+	q.lastError = err.Error()
+	q.lastErrorAt = time.Now()
+	exhausted := item.Attempts >= q.maxAttempts
+	q.mu.Unlock()
+
+	if exhausted {
+		q.giveUp(item, err)
+		return
+	}
+
+	backoff := q.backoff(item.Attempts)
+	q.mu.Lock()
+	item.NextAttempt = time.Now().Add(backoff)
+	q.mu.Unlock()
+	if pErr := q.persist(item); pErr != nil {
+		log.Printf("Falha ao atualizar alerta %s no spool do canal %s: %v", item.ID, q.channel, pErr)
+	}
+
+	log.Printf("Falha ao entregar alerta %s no canal %s (tentativa %d/%d, nova tentativa em %s): %v",
+		item.ID, q.channel, item.Attempts, q.maxAttempts, backoff, err)
+}
+
+// giveUp tira da fila o alerta que esgotou as tentativas e o encaminha ao
+// fallback do canal. Sem fallback, ou se o fallback recusar o alerta, ele vai
+// para o dead-letter.
+func (q *QueuedNotifier) giveUp(item *queuedAlert, err error) {
+	if q.fallback != nil {
+		fbErr := q.fallback.SendAlert(q.ctx, item.Alert)
+		if fbErr == nil {
+			q.remove(item)
+			q.mu.Lock()
+			q.pending = q.pending[1:]
+			q.fallenBack++
+			q.mu.Unlock()
+			log.Printf("Alerta %s encaminhado do canal %s ao fallback %s após %d tentativas: %v",
+				item.ID, q.channel, q.fallbackName, item.Attempts, err)
+			return
+		}
+		if q.ctx.Err() != nil {
+			// Interrompido pelo Close: o alerta continua no spool.
+			return
+		}
+		log.Printf("Fallback %s recusou o alerta %s do canal %s: %v", q.fallbackName, item.ID, q.channel, fbErr)
+	}
+
+	if dlErr := q.deadLetter(item); dlErr != nil {
+		log.Printf("Falha ao mover alerta %s para dead-letter do canal %s: %v", item.ID, q.channel, dlErr)
+	}
+	q.mu.Lock()
+	q.pending = q.pending[1:]
+	q.deadLettered++
+	q.mu.Unlock()
+	log.Printf("Alerta %s descartado no canal %s após %d tentativas: %v", item.ID, q.channel, item.Attempts, err)
+}
+
+func (q *QueuedNotifier) remove(item *queuedAlert) {
+	if err := os.Remove(q.spoolPath(item)); err != nil && !os.IsNotExist(err) {
+		log.Printf("Falha ao remover alerta %s do spool do canal %s: %v", item.ID, q.channel, err)
+	}
+}
+
+func (q *QueuedNotifier) backoff(attempts int) time.Duration {
+	backoff := q.backoffInitial
+	for i := 1; i < attempts && backoff < q.backoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > q.backoffMax {
+		backoff = q.backoffMax
+	}
+	return backoff
+}
+
+func (q *QueuedNotifier) spoolPath(item *queuedAlert) string {
+	return filepath.Join(q.dir, item.ID+".json")
+}
+
+// persist grava o registro em um arquivo temporário e renomeia, para que um
+// crash no meio da escrita não deixe JSON truncado no spool.
+func (q *QueuedNotifier) persist(item *queuedAlert) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	path := q.spoolPath(item)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (q *QueuedNotifier) deadLetter(item *queuedAlert) error {
+	if err := q.persist(item); err != nil {
+		return err
+	}
+	return os.Rename(q.spoolPath(item), filepath.Join(q.dir, deadLetterDirName, item.ID+".json"))
+}
+
+func (q *QueuedNotifier) loadSpool() error {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return fmt.Errorf("falha ao ler spool do canal %s: %w", q.channel, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		path := filepath.Join(q.dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("falha ao ler %s: %w", path, err)
+		}
+
+		var item queuedAlert
+		if err := json.Unmarshal(data, &item); err != nil {
+			log.Printf("Alerta inválido no spool do canal %s movido para dead-letter: %s: %v", q.channel, entry.Name(), err)
+			os.Rename(path, filepath.Join(q.dir, deadLetterDirName, entry.Name()))
+			continue
+		}
+		q.pending = append(q.pending, &item)
+	}
+
+	// Os IDs começam pelo horário em nanossegundos, então a ordem
+	// lexicográfica preserva a ordem de chegada.
+	sort.Slice(q.pending, func(i, j int) bool {
+		return q.pending[i].ID < q.pending[j].ID
+	})
+
+	if len(q.pending) > 0 {
+		log.Printf("%d alerta(s) pendente(s) recarregado(s) do spool do canal %s", len(q.pending), q.channel)
+	}
+
+	return nil
+}
+
+func (q *QueuedNotifier) Stats() QueueStats {
+	deadLetters := 0
+	if entries, err := os.ReadDir(filepath.Join(q.dir, deadLetterDirName)); err == nil {
+		deadLetters = len(entries)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := QueueStats{
+		Channel:      q.channel,
+		Depth:        len(q.pending),
+		Delivered:    q.delivered,
+		Failures:     q.failures,
+		DeadLettered: q.deadLettered,
+		FallenBack:   q.fallenBack,
+		DeadLetters:  deadLetters,
+		LastError:    q.lastError,
+	}
+	if !q.lastErrorAt.IsZero() {
+		lastErrorAt := q.lastErrorAt
+		stats.LastErrorAt = &lastErrorAt
+	}
+	if len(q.pending) > 0 && q.pending[0].Attempts > 0 {
+		next := q.pending[0].NextAttempt
+		stats.NextAttemptAt = &next
+	}
+
+	return stats
+}
+
+func (q *QueuedNotifier) Close() {
+	q.closeOnce.Do(func() {
+		close(q.stop)
+		q.cancel()
+		<-q.done
+	})
+}
@@ -0,0 +1,269 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"dbMonitor/internal/config"
+)
+
+// flakyNotifier falha nas primeiras failures chamadas e depois entrega.
+type flakyNotifier struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	sent     []Alert
+}
+
+func (f *flakyNotifier) SendAlert(ctx context.Context, alert Alert) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("canal indisponível")
+	}
+	f.sent = append(f.sent, alert)
+	return nil
+}
+
+func (f *flakyNotifier) counts() (calls, sent int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls, len(f.sent)
+}
+
+// testQueueManager usa backoff em milissegundos para o teste não esperar os
+// segundos da configuração.
+func testQueueManager(t *testing.T, spoolDir string, maxAttempts int) *QueueManager {
+	t.Helper()
+
+	m := NewQueueManager(config.NotificationQueueConfig{SpoolDir: spoolDir, MaxAttempts: maxAttempts})
+	m.backoffInitial = time.Millisecond
+	m.backoffMax = 4 * time.Millisecond
+	t.Cleanup(m.Close)
+	return m
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("tempo esgotado esperando %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func spoolFiles(t *testing.T, dir string) int {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files++
+		}
+	}
+	return files
+}
+
+func TestQueueBackoff(t *testing.T) {
+	q := &QueuedNotifier{backoffInitial: 5 * time.Second, backoffMax: time.Minute}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 5 * time.Second},
+		{attempts: 2, want: 10 * time.Second},
+		{attempts: 3, want: 20 * time.Second},
+		{attempts: 4, want: 40 * time.Second},
+		{attempts: 5, want: time.Minute},
+		{attempts: 50, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := q.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, esperado %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestQueueRetryAndDeadLetter(t *testing.T) {
+	tests := []struct {
+		name           string
+		failures       int
+		wantCalls      int
+		wantSent       int
+		wantFailures   uint64
+		wantDeadLetter int
+	}{
+		{name: "entrega na primeira tentativa", failures: 0, wantCalls: 1, wantSent: 1},
+		{name: "entrega após retentativas", failures: 2, wantCalls: 3, wantSent: 1, wantFailures: 2},
+		{name: "dead-letter após maxAttempts", failures: 10, wantCalls: 3, wantFailures: 3, wantDeadLetter: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spoolDir := t.TempDir()
+			n := &flakyNotifier{failures: tt.failures}
+
+			q, err := testQueueManager(t, spoolDir, 3).Wrap("email", n)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := q.SendAlert(context.Background(), testAlert()); err != nil {
+				t.Fatalf("SendAlert: %v", err)
+			}
+			waitFor(t, "a fila esvaziar", func() bool { return q.Stats().Depth == 0 })
+
+			calls, sent := n.counts()
+			if calls != tt.wantCalls || sent != tt.wantSent {
+				t.Errorf("chamadas/entregas = %d/%d, esperado %d/%d", calls, sent, tt.wantCalls, tt.wantSent)
+			}
+
+			stats := q.Stats()
+			if stats.Failures != tt.wantFailures {
+				t.Errorf("failures = %d, esperado %d", stats.Failures, tt.wantFailures)
+			}
+			if stats.Delivered != uint64(tt.wantSent) {
+				t.Errorf("delivered = %d, esperado %d", stats.Delivered, tt.wantSent)
+			}
+			if stats.DeadLettered != uint64(tt.wantDeadLetter) || stats.DeadLetters != tt.wantDeadLetter {
+				t.Errorf("dead-letter = %d (%d arquivos), esperado %d", stats.DeadLettered, stats.DeadLetters, tt.wantDeadLetter)
+			}
+			if files := spoolFiles(t, filepath.Join(spoolDir, "email")); files != 0 {
+				t.Errorf("%d arquivo(s) restante(s) no spool", files)
+			}
+		})
+	}
+}
+
+func TestQueueSpoolReload(t *testing.T) {
+	spoolDir := t.TempDir()
+	dir := filepath.Join(spoolDir, "email")
+
+	// Um canal que sempre falha deixa os alertas no spool ao fechar.
+	down := testQueueManager(t, spoolDir, 1000)
+	q, err := down.Wrap("email", &flakyNotifier{failures: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, second := testAlert(), testAlert()
+	second.AlertType = "QUERY_ERROR"
+	for _, alert := range []Alert{first, second} {
+		if err := q.SendAlert(context.Background(), alert); err != nil {
+			t.Fatalf("SendAlert: %v", err)
+		}
+	}
+	waitFor(t, "a primeira falha", func() bool { return q.Stats().Failures > 0 })
+	down.Close()
+
+	if files := spoolFiles(t, dir); files != 2 {
+		t.Fatalf("%d arquivo(s) no spool, esperado 2", files)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "0-corrompido.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	n := &flakyNotifier{}
+	q, err = testQueueManager(t, spoolDir, 1000).Wrap("email", n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "a reentrega do spool", func() bool { return q.Stats().Depth == 0 })
+
+	_, sent := n.counts()
+	if sent != 2 {
+		t.Fatalf("%d alerta(s) reentregue(s), esperado 2", sent)
+	}
+	if n.sent[0].AlertType != first.AlertType || n.sent[1].AlertType != second.AlertType {
+		t.Errorf("ordem de reentrega = %s, %s", n.sent[0].AlertType, n.sent[1].AlertType)
+	}
+	if dead := q.Stats().DeadLetters; dead != 1 {
+		t.Errorf("%d dead-letter(s), esperado 1 (o JSON corrompido)", dead)
+	}
+}
+
+func TestQueueFallback(t *testing.T) {
+	tests := []struct {
+		name          string
+		queued        bool
+		slackFailures int
+		wantSlack     int // chamadas ao Slack
+		wantEmail     int // alertas entregues pelo email
+	}{
+		{name: "com fila, retenta o canal antes do fallback", queued: true, slackFailures: 1, wantSlack: 2},
+		{name: "com fila, fallback após esgotar as tentativas", queued: true, slackFailures: 10, wantSlack: 3, wantEmail: 1},
+		{name: "sem fila, fallback logo após a falha", slackFailures: 1, wantSlack: 1, wantEmail: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slack := &flakyNotifier{failures: tt.slackFailures}
+			email := &flakyNotifier{}
+
+			channels := NewChannelSet(map[string]config.ChannelConfig{"slack": {Fallback: "email"}})
+			channels.Add("slack", slack)
+			channels.Add("email", email)
+
+			m := testQueueManager(t, t.TempDir(), 3)
+			deliveries := make(map[string]Notifier)
+			for _, name := range channels.Names() {
+				c, _ := channels.Get(name)
+				deliveries[name] = c
+				if tt.queued {
+					q, err := m.Wrap(name, c)
+					if err != nil {
+						t.Fatal(err)
+					}
+					deliveries[name] = q
+				}
+			}
+			channels.LinkFallbacks(deliveries)
+
+			if err := deliveries["slack"].SendAlert(context.Background(), testAlert()); err != nil {
+				t.Fatalf("SendAlert: %v", err)
+			}
+			waitFor(t, "as filas esvaziarem", func() bool {
+				for _, s := range m.Stats() {
+					if s.Depth > 0 {
+						return false
+					}
+				}
+				return true
+			})
+
+			if calls, _ := slack.counts(); calls != tt.wantSlack {
+				t.Errorf("Slack chamado %d vezes, esperado %d", calls, tt.wantSlack)
+			}
+			if _, sent := email.counts(); sent != tt.wantEmail {
+				t.Errorf("email entregou %d alertas, esperado %d", sent, tt.wantEmail)
+			}
+			for _, s := range m.Stats() {
+				if s.DeadLettered != 0 {
+					t.Errorf("fila %s com %d dead-letter(s)", s.Channel, s.DeadLettered)
+				}
+				if s.Channel == "slack" && s.FallenBack != uint64(tt.wantEmail) {
+					t.Errorf("fallen_back = %d, esperado %d", s.FallenBack, tt.wantEmail)
+				}
+			}
+			if health, _ := channels.Get("slack"); health.Health().Fallback != "email" {
+				t.Errorf("fallback na saúde do canal = %q", health.Health().Fallback)
+			}
+		})
+	}
+}
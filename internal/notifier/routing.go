@@ -0,0 +1,141 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"dbMonitor/internal/config"
+)
+
+// RoutingTree encaminha alertas no estilo do Alertmanager: a partir da raiz,
+// cada rota filha que casa com o alerta é visitada em ordem e a busca para na
+// primeira que casar, a menos que ela tenha continue. Uma rota cujo alerta
+// não casou com nenhuma filha usa os próprios receivers. A raiz casa com
+// todos os alertas.
+type RoutingTree struct {
+	root      *routeNode
+	receivers map[string][]Notifier
+}
+
+type routeNode struct {
+	match     config.RouteMatch
+	receivers []string
+	cont      bool
+	children  []*routeNode
+}
+
+// NewRoutingTree monta a árvore a partir da configuração. channels associa o
+// nome de cada canal ativo ao notifier que o entrega (já com fila, se houver).
+// Canais citados em receivers mas inativos são ignorados com um aviso.
+func NewRoutingTree(route config.RouteConfig, receivers []config.ReceiverConfig, channels map[string]Notifier) (*RoutingTree, error) {
+	t := &RoutingTree{
+		root:      buildRouteNode(route),
+		receivers: make(map[string][]Notifier),
+	}
+	t.root.match = config.RouteMatch{}
+
+	for _, r := range receivers {
+		for _, name := range r.Channels {
+			n, ok := channels[name]
+			if !ok {
+				log.Printf("Warning: canal %s do receiver %s não está ativo", name, r.Name)
+				continue
+			}
+			t.receivers[r.Name] = append(t.receivers[r.Name], n)
+		}
+	}
+
+	if len(route.Receivers) == 0 {
+		return nil, fmt.Errorf("a rota raiz precisa de ao menos um receiver")
+	}
+
+	return t, nil
+}
+
+func buildRouteNode(cfg config.RouteConfig) *routeNode {
+	node := &routeNode{
+		match:     cfg.Match,
+		receivers: cfg.Receivers,
+		cont:      cfg.Continue,
+	}
+	for _, child := range cfg.Routes {
+		node.children = append(node.children, buildRouteNode(child))
+	}
+	return node
+}
+
+func (n *routeNode) route(alert Alert, receivers *[]string) bool {
+	if !n.match.Matches(alert.DatabaseName, alert.AlertType, string(alert.Severity), alert.Labels) {
+		return false
+	}
+
+	matchedChild := false
+	for _, child := range n.children {
+		if child.route(alert, receivers) {
+			matchedChild = true
+			if !child.cont {
+				break
+			}
+		}
+	}
+
+	if !matchedChild {
+		*receivers = append(*receivers, n.receivers...)
+	}
+
+	return true
+}
+
+// Receivers retorna os receivers escolhidos para o alerta, sem repetição. Uma
+// resolução é roteada com cada severidade notificada, para chegar a todos os
+// receivers que receberam o disparo.
+func (t *RoutingTree) Receivers(alert Alert) []string {
+	var matched []string
+	for _, severity := range alert.routingSeverities() {
+		routed := alert
+		routed.Severity = severity
+		t.root.route(routed, &matched)
+	}
+
+	seen := make(map[string]bool)
+	receivers := matched[:0]
+	for _, r := range matched {
+		if !seen[r] {
+			seen[r] = true
+			receivers = append(receivers, r)
+		}
+	}
+	return receivers
+}
+
+// SendAlert entrega o alerta, em paralelo, aos canais dos receivers
+// escolhidos. Um canal presente em mais de um receiver recebe uma vez só.
+func (t *RoutingTree) SendAlert(ctx context.Context, alert Alert) error {
+	return deliveryError(t.Deliver(ctx, alert))
+}
+
+func (t *RoutingTree) Deliver(ctx context.Context, alert Alert) []ChannelResult {
+	seen := make(map[Notifier]bool)
+	var targets []Notifier
+	for _, receiver := range t.Receivers(alert) {
+		for _, n := range t.receivers[receiver] {
+			if !seen[n] {
+				seen[n] = true
+				targets = append(targets, n)
+			}
+		}
+	}
+
+	if len(targets) == 0 {
+		// Sem canal o alerta não foi entregue: o resultado falho mantém a
+		// notificação devida no monitor.
+		log.Printf("Nenhum canal ativo para o alerta %s de %s", alert.AlertType, alert.DatabaseName)
+		return []ChannelResult{{
+			Channel:  "route",
+			Attempts: []DeliveryAttempt{{Channel: "route", Error: "nenhum canal ativo para o alerta"}},
+		}}
+	}
+
+	return fanOut(ctx, alert, targets)
+}
@@ -0,0 +1,240 @@
+package notifier
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"dbMonitor/internal/config"
+)
+
+// testRoute é a árvore usada nos testes: críticos da produção vão para o
+// plantão e, com continue, também para o time de banco; alertas de
+// replicação vão para o time de banco; o resto fica com o receiver padrão.
+func testRoute() config.RouteConfig {
+	return config.RouteConfig{
+		Receivers: []string{"padrao"},
+		Routes: []config.RouteConfig{
+			{
+				Match:     config.RouteMatch{Labels: map[string]string{"env": "prod"}, Severities: []string{"critical"}},
+				Receivers: []string{"plantao"},
+				Continue:  true,
+			},
+			{
+				Match:     config.RouteMatch{AlertTypes: []string{"REPLICATION_LAG", "HIGH_TOTAL_CONNECTIONS"}},
+				Receivers: []string{"dba"},
+				Routes: []config.RouteConfig{
+					{
+						Match:     config.RouteMatch{Databases: []string{"mysql_legado"}},
+						Receivers: []string{"legado"},
+					},
+				},
+			},
+			{
+				Match:     config.RouteMatch{Severities: []string{"critical"}},
+				Receivers: []string{"plantao", "dba"},
+			},
+		},
+	}
+}
+
+func TestRoutingTreeReceivers(t *testing.T) {
+	prod := map[string]string{"env": "prod"}
+
+	tests := []struct {
+		name  string
+		alert Alert
+		want  []string
+	}{
+		{
+			name:  "sem rota filha usa o receiver da raiz",
+			alert: Alert{DatabaseName: "pg", AlertType: "HIGH_ACTIVE_CONNECTIONS", Severity: SeverityWarning},
+			want:  []string{"padrao"},
+		},
+		{
+			name:  "primeira rota que casa encerra a busca",
+			alert: Alert{DatabaseName: "pg", AlertType: "REPLICATION_LAG", Severity: SeverityCritical},
+			want:  []string{"dba"},
+		},
+		{
+			name:  "continue segue para as rotas seguintes",
+			alert: Alert{DatabaseName: "pg", AlertType: "REPLICATION_LAG", Severity: SeverityCritical, Labels: prod},
+			want:  []string{"plantao", "dba"},
+		},
+		{
+			name:  "continue sem outra rota que case",
+			alert: Alert{DatabaseName: "pg", AlertType: "QUERY_ERROR", Severity: SeverityCritical, Labels: prod},
+			want:  []string{"plantao", "dba"},
+		},
+		{
+			name:  "rota filha mais específica substitui a do pai",
+			alert: Alert{DatabaseName: "mysql_legado", AlertType: "HIGH_TOTAL_CONNECTIONS", Severity: SeverityWarning},
+			want:  []string{"legado"},
+		},
+		{
+			name:  "labels precisam casar todos",
+			alert: Alert{DatabaseName: "pg", AlertType: "QUERY_ERROR", Severity: SeverityWarning, Labels: prod},
+			want:  []string{"padrao"},
+		},
+		{
+			name: "resolução usa as severidades notificadas",
+			alert: Alert{
+				DatabaseName:       "pg",
+				AlertType:          "HIGH_ACTIVE_CONNECTIONS",
+				Severity:           SeverityWarning,
+				State:              AlertStateResolved,
+				NotifiedSeverities: []Severity{SeverityCritical, SeverityWarning},
+			},
+			want: []string{"plantao", "dba", "padrao"},
+		},
+	}
+
+	tree, err := NewRoutingTree(testRoute(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tree.Receivers(tt.alert)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("receivers = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoutingTreeRootMatchesEverything(t *testing.T) {
+	route := testRoute()
+	route.Match = config.RouteMatch{Databases: []string{"outra"}}
+
+	tree, err := NewRoutingTree(route, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alert := Alert{DatabaseName: "pg", AlertType: "QUERY_ERROR", Severity: SeverityWarning}
+	if got := tree.Receivers(alert); !slices.Equal(got, []string{"padrao"}) {
+		t.Fatalf("receivers = %v, esperado [padrao]", got)
+	}
+}
+
+func TestRoutingTreeDeliver(t *testing.T) {
+	tests := []struct {
+		name    string
+		alert   Alert
+		want    map[string]int
+		wantErr bool
+	}{
+		{
+			name:  "canal em dois receivers recebe uma vez",
+			alert: Alert{DatabaseName: "pg", AlertType: "QUERY_ERROR", Severity: SeverityCritical},
+			want:  map[string]int{"email": 1, "pagerduty": 1, "slack": 0},
+		},
+		{
+			name:  "warning vai só ao receiver padrão",
+			alert: Alert{DatabaseName: "pg", AlertType: "QUERY_ERROR", Severity: SeverityWarning},
+			want:  map[string]int{"email": 0, "pagerduty": 0, "slack": 1},
+		},
+		{
+			name: "resolução de alerta rebaixado chega aos canais do critical",
+			alert: Alert{
+				DatabaseName:       "pg",
+				AlertType:          "QUERY_ERROR",
+				Severity:           SeverityWarning,
+				State:              AlertStateResolved,
+				NotifiedSeverities: []Severity{SeverityCritical},
+			},
+			want: map[string]int{"email": 1, "pagerduty": 1, "slack": 0},
+		},
+		{
+			name:    "receiver sem canal ativo não conta como entregue",
+			alert:   Alert{DatabaseName: "mysql_legado", AlertType: "HIGH_TOTAL_CONNECTIONS", Severity: SeverityWarning},
+			want:    map[string]int{"email": 0, "pagerduty": 0, "slack": 0},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channels := map[string]*MockNotifier{
+				"email":     NewMockNotifier(),
+				"pagerduty": NewMockNotifier(),
+				"slack":     NewMockNotifier(),
+			}
+			notifiers := make(map[string]Notifier, len(channels))
+			for name, n := range channels {
+				notifiers[name] = n
+			}
+
+			tree, err := NewRoutingTree(testRoute(), []config.ReceiverConfig{
+				{Name: "padrao", Channels: []string{"slack"}},
+				{Name: "plantao", Channels: []string{"pagerduty", "email"}},
+				{Name: "dba", Channels: []string{"email"}},
+				{Name: "legado", Channels: []string{"inativo"}},
+			}, notifiers)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = tree.SendAlert(context.Background(), tt.alert)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendAlert erro = %v, esperado erro: %v", err, tt.wantErr)
+			}
+			for name, want := range tt.want {
+				if got := channels[name].GetAlertCount(); got != want {
+					t.Errorf("%s recebeu %d alertas, esperado %d", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSeverityRouterResolved(t *testing.T) {
+	tests := []struct {
+		name         string
+		alert        Alert
+		wantWarning  int
+		wantCritical int
+	}{
+		{
+			name:        "disparo vai à rota da severidade",
+			alert:       Alert{Severity: SeverityWarning},
+			wantWarning: 1,
+		},
+		{
+			name:         "resolução sem severidades notificadas usa a atual",
+			alert:        Alert{Severity: SeverityCritical, State: AlertStateResolved},
+			wantCritical: 1,
+		},
+		{
+			name: "resolução vai a todas as severidades notificadas",
+			alert: Alert{
+				Severity:           SeverityWarning,
+				State:              AlertStateResolved,
+				NotifiedSeverities: []Severity{SeverityCritical, SeverityWarning},
+			},
+			wantWarning:  1,
+			wantCritical: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning, critical := NewMockNotifier(), NewMockNotifier()
+			router := NewSeverityRouter()
+			router.Route(warning, "warning")
+			router.Route(critical, "critical")
+
+			if err := router.SendAlert(context.Background(), tt.alert); err != nil {
+				t.Fatalf("SendAlert: %v", err)
+			}
+			if got := warning.GetAlertCount(); got != tt.wantWarning {
+				t.Errorf("warning recebeu %d, esperado %d", got, tt.wantWarning)
+			}
+			if got := critical.GetAlertCount(); got != tt.wantCritical {
+				t.Errorf("critical recebeu %d, esperado %d", got, tt.wantCritical)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"dbMonitor/internal/config"
+)
+
+const defaultSlackAPIURL = "https://slack.com/api"
+
+// slackAPI publica alertas pelo chat.postMessage usando um bot token. O ts da
+// primeira mensagem de cada alerta é guardado para que repetições e a
+// resolução entrem como respostas na thread, e a mensagem original é editada
+// ao resolver. As threads ficam só em memória: após um restart, o próximo
+// disparo abre uma mensagem nova.
+type slackAPI struct {
+	token   string
+	channel string
+	baseURL string
+	client  *http.Client
+
+	mu      sync.Mutex
+	threads map[string]string
+}
+
+func newSlackAPI(cfg config.SlackConfig) *slackAPI {
+	baseURL := cfg.APIURL
+	if baseURL == "" {
+		baseURL = defaultSlackAPIURL
+	}
+
+	return &slackAPI{
+		token:   cfg.BotToken,
+		channel: cfg.Channel,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+		threads: make(map[string]string),
+	}
+}
+
+type slackAPIResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+func slackThreadKey(alert Alert) string {
+	return alert.DatabaseName + "/" + alert.AlertType
+}
+
+func (s *slackAPI) send(ctx context.Context, alert Alert, title, text string) error {
+	key := slackThreadKey(alert)
+
+	s.mu.Lock()
+	parentTS, hasThread := s.threads[key]
+	s.mu.Unlock()
+
+	blocks := renderSlackBlocks(alert, title, text)
+
+	if !hasThread {
+		if alert.IsResolved() {
+			// Sem a mensagem original (ex.: após restart) a resolução é
+			// publicada como mensagem avulsa.
+			_, err := s.postMessage(ctx, title, blocks, "")
+			return err
+		}
+
+		ts, err := s.postMessage(ctx, title, blocks, "")
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.threads[key] = ts
+		s.mu.Unlock()
+
+		log.Printf("Mensagem do Slack publicada: %s", title)
+		return nil
+	}
+
+	if _, err := s.postMessage(ctx, title, blocks, parentTS); err != nil {
+		return err
+	}
+
+	if !alert.IsResolved() {
+		log.Printf("Repetição publicada na thread do Slack: %s", title)
+		return nil
+	}
+
+	s.mu.Lock()
+	delete(s.threads, key)
+	s.mu.Unlock()
+
+	if err := s.updateMessage(ctx, parentTS, title, blocks); err != nil {
+		return fmt.Errorf("falha ao atualizar mensagem original do Slack: %w", err)
+	}
+
+	log.Printf("Alerta resolvido na thread do Slack: %s", title)
+	return nil
+}
+
+func (s *slackAPI) postMessage(ctx context.Context, text string, blocks []map[string]interface{}, threadTS string) (string, error) {
+	payload := map[string]interface{}{
+		"channel": s.channel,
+		"text":    text,
+		"blocks":  blocks,
+	}
+	if threadTS != "" {
+		payload["thread_ts"] = threadTS
+	}
+
+	resp, err := s.call(ctx, "chat.postMessage", payload)
+	if err != nil {
+		return "", err
+	}
+
+	// O channel da resposta é o ID, exigido pelo chat.update quando o
+	// canal foi configurado pelo nome.
+	if resp.Channel != "" {
+		s.mu.Lock()
+		s.channel = resp.Channel
+		s.mu.Unlock()
+	}
+
+	return resp.TS, nil
+}
+
+func (s *slackAPI) updateMessage(ctx context.Context, ts, text string, blocks []map[string]interface{}) error {
+	s.mu.Lock()
+	channel := s.channel
+	s.mu.Unlock()
+
+	_, err := s.call(ctx, "chat.update", map[string]interface{}{
+		"channel": channel,
+		"ts":      ts,
+		"text":    text,
+		"blocks":  blocks,
+	})
+	return err
+}
+
+func (s *slackAPI) call(ctx context.Context, method string, payload map[string]interface{}) (*slackAPIResponse, error) {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao montar payload do Slack: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/"+method, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar requisição para o Slack: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao enviar notificação para o Slack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("erro do servidor Slack: %s", resp.Status)
+	}
+
+	var result slackAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("resposta inválida do Slack: %w", err)
+	}
+	if !result.OK {
+		return nil, fmt.Errorf("erro do Slack em %s: %s", method, result.Error)
+	}
+
+	return &result, nil
+}
+
+var slackSeverityEmojis = map[Severity]string{
+	SeverityWarning:  ":warning:",
+	SeverityCritical: ":red_circle:",
+}
+
+// renderSlackBlocks monta a mensagem em Block Kit: cabeçalho, texto do
+// alerta, campos e o horário como contexto.
+func renderSlackBlocks(alert Alert, title, text string) []map[string]interface{} {
+	emoji := slackSeverityEmojis[alert.Severity]
+	if alert.IsResolved() {
+		emoji = ":white_check_mark:"
+	}
+
+	// O bloco header aceita no máximo 150 caracteres e o texto de uma
+	// section, 3000; acima disso o Slack rejeita a mensagem (invalid_blocks).
+	header := truncate(strings.TrimSpace(emoji+" "+title), 150)
+	text = truncate(text, 3000)
+
+	fields := make([]map[string]interface{}, 0, 6)
+	for _, f := range slackFields(alert) {
+		fields = append(fields, map[string]interface{}{
+			"type": "mrkdwn",
+			"text": fmt.Sprintf("*%s*\n%s", f["title"], f["value"]),
+		})
+	}
+
+	context := fmt.Sprintf("Started %s", alert.StartedAt.Format("2006-01-02 15:04:05"))
+	if alert.IsResolved() {
+		context = fmt.Sprintf("%s · resolved %s after %s", context,
+			alert.Timestamp.Format("2006-01-02 15:04:05"),
+			alert.Timestamp.Sub(alert.StartedAt).Round(time.Second))
+	} else {
+		context = fmt.Sprintf("%s · last seen %s", context, alert.Timestamp.Format("2006-01-02 15:04:05"))
+	}
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": header, "emoji": true},
+		},
+	}
+	if text != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": text},
+		})
+	}
+	blocks = append(blocks,
+		map[string]interface{}{"type": "section", "fields": fields},
+		map[string]interface{}{
+			"type":     "context",
+			"elements": []map[string]interface{}{{"type": "mrkdwn", "text": context}},
+		},
+	)
+
+	return blocks
+}
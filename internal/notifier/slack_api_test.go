@@ -0,0 +1,161 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"dbMonitor/internal/config"
+)
+
+const testSlackTS = "1760608800.000100"
+
+// slackCall é uma chamada recebida pela Web API falsa do Slack.
+type slackCall struct {
+	method   string
+	channel  string
+	ts       string
+	threadTS string
+	header   string
+}
+
+func testSlackAPI(t *testing.T, response string) (*SlackNotifier, <-chan capturedRequest) {
+	t.Helper()
+
+	server, requests := testServer(t, http.StatusOK, response)
+	s, err := NewSlackNotifier(config.SlackConfig{BotToken: "xoxb-teste", Channel: "#alertas", APIURL: server.URL + "/"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, requests
+}
+
+// slackCalls lê as chamadas já recebidas pelo servidor.
+func slackCalls(t *testing.T, requests <-chan capturedRequest) []slackCall {
+	t.Helper()
+
+	var calls []slackCall
+	for {
+		select {
+		case req := <-requests:
+			if auth := req.header.Get("Authorization"); auth != "Bearer xoxb-teste" {
+				t.Errorf("Authorization = %q", auth)
+			}
+
+			var payload struct {
+				Channel  string `json:"channel"`
+				TS       string `json:"ts"`
+				ThreadTS string `json:"thread_ts"`
+				Blocks   []struct {
+					Type string `json:"type"`
+					Text struct {
+						Text string `json:"text"`
+					} `json:"text"`
+				} `json:"blocks"`
+			}
+			if err := json.Unmarshal(req.body, &payload); err != nil {
+				t.Fatalf("payload inválido: %v", err)
+			}
+			call := slackCall{
+				method:   strings.TrimPrefix(req.path, "/"),
+				channel:  payload.Channel,
+				ts:       payload.TS,
+				threadTS: payload.ThreadTS,
+			}
+			if len(payload.Blocks) > 0 && payload.Blocks[0].Type == "header" {
+				call.header = payload.Blocks[0].Text.Text
+			}
+			calls = append(calls, call)
+		default:
+			return calls
+		}
+	}
+}
+
+func TestSlackAPIThread(t *testing.T) {
+	s, requests := testSlackAPI(t, `{"ok":true,"channel":"C0123","ts":"`+testSlackTS+`"}`)
+
+	firing := testAlert()
+	resolved := testAlert()
+	resolved.State = AlertStateResolved
+
+	steps := []struct {
+		name  string
+		alert Alert
+		want  []slackCall
+	}{
+		{
+			name:  "disparo abre a mensagem",
+			alert: firing,
+			want:  []slackCall{{method: "chat.postMessage", channel: "#alertas"}},
+		},
+		{
+			name:  "repetição entra na thread",
+			alert: firing,
+			want:  []slackCall{{method: "chat.postMessage", channel: "C0123", threadTS: testSlackTS}},
+		},
+		{
+			name:  "resolução responde na thread e edita a mensagem original",
+			alert: resolved,
+			want: []slackCall{
+				{method: "chat.postMessage", channel: "C0123", threadTS: testSlackTS},
+				{method: "chat.update", channel: "C0123", ts: testSlackTS},
+			},
+		},
+		{
+			name:  "novo disparo abre outra mensagem",
+			alert: firing,
+			want:  []slackCall{{method: "chat.postMessage", channel: "C0123"}},
+		},
+	}
+
+	for _, step := range steps {
+		if err := s.SendAlert(context.Background(), step.alert); err != nil {
+			t.Fatalf("%s: SendAlert: %v", step.name, err)
+		}
+
+		calls := slackCalls(t, requests)
+		if len(calls) != len(step.want) {
+			t.Fatalf("%s: %d chamada(s), esperado %d: %+v", step.name, len(calls), len(step.want), calls)
+		}
+		for i, want := range step.want {
+			got := calls[i]
+			if got.method != want.method || got.channel != want.channel || got.ts != want.ts || got.threadTS != want.threadTS {
+				t.Errorf("%s: chamada %d = %+v, esperado %+v", step.name, i, got, want)
+			}
+			wantEmoji := ":red_circle:"
+			if step.alert.IsResolved() {
+				wantEmoji = ":white_check_mark:"
+			}
+			if !strings.HasPrefix(got.header, wantEmoji) {
+				t.Errorf("%s: cabeçalho %q sem %s", step.name, got.header, wantEmoji)
+			}
+		}
+	}
+}
+
+func TestSlackAPIResolvedWithoutThread(t *testing.T) {
+	s, requests := testSlackAPI(t, `{"ok":true,"channel":"C0123","ts":"`+testSlackTS+`"}`)
+
+	resolved := testAlert()
+	resolved.State = AlertStateResolved
+	if err := s.SendAlert(context.Background(), resolved); err != nil {
+		t.Fatalf("SendAlert: %v", err)
+	}
+
+	calls := slackCalls(t, requests)
+	if len(calls) != 1 || calls[0].method != "chat.postMessage" || calls[0].threadTS != "" {
+		t.Fatalf("chamadas = %+v, esperado uma mensagem avulsa", calls)
+	}
+}
+
+func TestSlackAPIError(t *testing.T) {
+	s, _ := testSlackAPI(t, `{"ok":false,"error":"channel_not_found"}`)
+
+	err := s.SendAlert(context.Background(), testAlert())
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Fatalf("erro = %v, esperado o erro do Slack", err)
+	}
+}
@@ -0,0 +1,279 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/smtp"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"dbMonitor/internal/config"
+	"gopkg.in/gomail.v2"
+)
+
+const (
+	smtpDialTimeout = 10 * time.Second
+	// smtpSessionTimeout limita a sessão inteira (STARTTLS, AUTH e DATA), para
+	// que um relay travado não prenda o envio indefinidamente.
+	smtpSessionTimeout = 60 * time.Second
+)
+
+// smtpDialer substitui o gomail.Dialer para aplicar a política de TLS
+// configurada: sem TLS, STARTTLS oportunista, STARTTLS obrigatório ou TLS
+// implícito (porta 465).
+type smtpDialer struct {
+	host          string
+	port          int
+	username      string
+	password      string
+	tlsMode       string
+	tlsConfig     *tls.Config
+	authMechanism string
+}
+
+func newSMTPDialer(cfg config.EmailConfig) (*smtpDialer, error) {
+	tlsConfig, err := loadSMTPTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &smtpDialer{
+		host:          cfg.SMTPHost,
+		port:          cfg.SMTPPort,
+		username:      cfg.Username,
+		password:      cfg.Password,
+		tlsMode:       cfg.EffectiveTLSMode(),
+		tlsConfig:     tlsConfig,
+		authMechanism: strings.ToUpper(cfg.AuthMechanism),
+	}, nil
+}
+
+func loadSMTPTLSConfig(cfg config.EmailConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{ServerName: cfg.SMTPHost}
+	if cfg.ServerName != "" {
+		tlsConfig.ServerName = cfg.ServerName
+	}
+
+	if cfg.CAFile != "" {
+		caCert, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler CA do SMTP: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("falha ao interpretar CA do SMTP: %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao carregar certificado de cliente do SMTP: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// Dial abre a sessão SMTP. O prazo da sessão é o menor entre
+// smtpSessionTimeout e o do contexto, e o cancelamento do contexto interrompe
+// a sessão em andamento.
+func (d *smtpDialer) Dial(ctx context.Context) (*smtpSender, error) {
+	dialer := net.Dialer{Timeout: smtpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(d.host, strconv.Itoa(d.port)))
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(smtpSessionTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+
+	sender, err := d.handshake(conn)
+	if err != nil {
+		stop()
+		conn.Close()
+		return nil, err
+	}
+	sender.stop = stop
+
+	return sender, nil
+}
+
+func (d *smtpDialer) handshake(conn net.Conn) (*smtpSender, error) {
+	if d.tlsMode == config.SMTPTLSImplicit {
+		conn = tls.Client(conn, d.tlsConfig)
+	}
+
+	c, err := smtp.NewClient(conn, d.host)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.tlsMode == config.SMTPTLSStartTLS || d.tlsMode == config.SMTPTLSStartTLSRequired {
+		ok, _ := c.Extension("STARTTLS")
+		if ok {
+			if err := c.StartTLS(d.tlsConfig); err != nil {
+				c.Close()
+				return nil, fmt.Errorf("falha no STARTTLS: %w", err)
+			}
+		} else if d.tlsMode == config.SMTPTLSStartTLSRequired {
+			c.Close()
+			return nil, errors.New("servidor SMTP não suporta STARTTLS")
+		}
+	}
+
+	if d.username != "" {
+		auth, err := d.auth(c)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		if auth != nil {
+			if err := c.Auth(auth); err != nil {
+				c.Close()
+				return nil, fmt.Errorf("falha na autenticação SMTP: %w", err)
+			}
+		}
+	}
+
+	return &smtpSender{client: c}, nil
+}
+
+// auth escolhe o mecanismo configurado ou, sem configuração, o melhor
+// anunciado pelo servidor: com TLS, PLAIN (ou LOGIN); sem TLS, CRAM-MD5, que
+// não expõe a senha.
+func (d *smtpDialer) auth(c *smtp.Client) (smtp.Auth, error) {
+	ok, mechanisms := c.Extension("AUTH")
+	if !ok {
+		if d.authMechanism != "" {
+			return nil, errors.New("servidor SMTP não suporta autenticação")
+		}
+		return nil, nil
+	}
+
+	mechanism := d.authMechanism
+	if mechanism == "" {
+		_, isTLS := c.TLSConnectionState()
+		advertised := strings.Fields(mechanisms)
+		switch {
+		case isTLS && slices.Contains(advertised, "PLAIN"):
+			mechanism = "PLAIN"
+		case isTLS && slices.Contains(advertised, "LOGIN"):
+			mechanism = "LOGIN"
+		case slices.Contains(advertised, "CRAM-MD5"):
+			mechanism = "CRAM-MD5"
+		case slices.Contains(advertised, "LOGIN") && !slices.Contains(advertised, "PLAIN"):
+			mechanism = "LOGIN"
+		default:
+			mechanism = "PLAIN"
+		}
+	}
+
+	switch mechanism {
+	case "PLAIN":
+		return smtp.PlainAuth("", d.username, d.password, d.host), nil
+	case "LOGIN":
+		return &loginAuth{username: d.username, password: d.password}, nil
+	case "CRAM-MD5":
+		return smtp.CRAMMD5Auth(d.username, d.password), nil
+	default:
+		return nil, fmt.Errorf("mecanismo de autenticação SMTP desconhecido: %s", mechanism)
+	}
+}
+
+func (d *smtpDialer) DialAndSend(ctx context.Context, m ...*gomail.Message) error {
+	s, err := d.Dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	return gomail.Send(s, m...)
+}
+
+type smtpSender struct {
+	client *smtp.Client
+	stop   func() bool
+}
+
+func (s *smtpSender) Send(from string, to []string, msg io.WriterTo) error {
+	if err := s.client.Mail(from); err != nil {
+		return err
+	}
+
+	for _, addr := range to {
+		if err := s.client.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+
+	w, err := s.client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := msg.WriteTo(w); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// Close encerra a sessão com QUIT. Se o QUIT falhar (ex.: o servidor derrubou
+// a conexão), a conexão é fechada mesmo assim e o erro do QUIT é retornado.
+func (s *smtpSender) Close() error {
+	s.stop()
+	if err := s.client.Quit(); err != nil {
+		s.client.Close()
+		return err
+	}
+	return nil
+}
+
+// loginAuth implementa o mecanismo AUTH LOGIN, que o net/smtp não oferece.
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS {
+		return "", nil, errors.New("AUTH LOGIN exige conexão TLS")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("desafio AUTH LOGIN inesperado: %s", fromServer)
+	}
+}
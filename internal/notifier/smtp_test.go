@@ -0,0 +1,267 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dbMonitor/internal/config"
+)
+
+// fakeSMTP é um servidor SMTP mínimo que anuncia STARTTLS e os mecanismos de
+// AUTH configurados e registra o que aconteceu na sessão.
+type fakeSMTP struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	caFile    string
+
+	startTLS   bool
+	mechanisms string
+	failQuit   bool
+
+	sessions chan smtpSession
+}
+
+type smtpSession struct {
+	tls       bool
+	mechanism string
+	data      string
+	closed    bool // o cliente fechou a conexão após o QUIT recusado
+}
+
+func newFakeSMTP(t *testing.T, startTLS bool, mechanisms string) *fakeSMTP {
+	t.Helper()
+
+	// O certificado do httptest vale para 127.0.0.1.
+	certServer := httptest.NewTLSServer(nil)
+	t.Cleanup(certServer.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certServer.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &fakeSMTP{
+		listener:   listener,
+		tlsConfig:  &tls.Config{Certificates: certServer.TLS.Certificates},
+		caFile:     caFile,
+		startTLS:   startTLS,
+		mechanisms: mechanisms,
+		sessions:   make(chan smtpSession, 1),
+	}
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTP) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTP) emailConfig(tlsMode, authMechanism string) config.EmailConfig {
+	return config.EmailConfig{
+		SMTPHost:      "127.0.0.1",
+		SMTPPort:      s.port(),
+		Username:      "monitor",
+		Password:      "segredo",
+		FromEmail:     "monitor@exemplo.com",
+		ToEmails:      []string{"dba@exemplo.com"},
+		TLSMode:       tlsMode,
+		CAFile:        s.caFile,
+		AuthMechanism: authMechanism,
+	}
+}
+
+func (s *fakeSMTP) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	var session smtpSession
+	defer func() { s.sessions <- session }()
+
+	r := bufio.NewReader(conn)
+	w := conn
+	reply := func(line string) { w.Write([]byte(line + "\r\n")) }
+	readLine := func() (string, bool) {
+		line, err := r.ReadString('\n')
+		return strings.TrimRight(line, "\r\n"), err == nil
+	}
+
+	reply("220 fake ESMTP")
+	for {
+		line, ok := readLine()
+		if !ok {
+			return
+		}
+		cmd := strings.ToUpper(strings.Fields(line + " ")[0])
+
+		switch cmd {
+		case "EHLO":
+			reply("250-fake")
+			if s.startTLS && !session.tls {
+				reply("250-STARTTLS")
+			}
+			if s.mechanisms != "" {
+				reply("250-AUTH " + s.mechanisms)
+			}
+			reply("250 8BITMIME")
+		case "STARTTLS":
+			reply("220 pronto")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			session.tls = true
+			r = bufio.NewReader(tlsConn)
+			w = tlsConn
+		case "AUTH":
+			args := strings.Fields(line)
+			session.mechanism = args[1]
+			switch session.mechanism {
+			case "CRAM-MD5":
+				reply("334 " + base64.StdEncoding.EncodeToString([]byte("<1@fake>")))
+				readLine()
+			case "LOGIN":
+				reply("334 " + base64.StdEncoding.EncodeToString([]byte("Username:")))
+				readLine()
+				reply("334 " + base64.StdEncoding.EncodeToString([]byte("Password:")))
+				readLine()
+			}
+			reply("235 autenticado")
+		case "DATA":
+			reply("354 envie")
+			var data strings.Builder
+			for {
+				l, ok := readLine()
+				if !ok {
+					return
+				}
+				if l == "." {
+					break
+				}
+				data.WriteString(l + "\n")
+			}
+			session.data = data.String()
+			reply("250 aceito")
+		case "QUIT":
+			if !s.failQuit {
+				reply("221 tchau")
+				return
+			}
+			reply("451 falha local")
+			// Só EOF indica que o cliente fechou a conexão; estourar o
+			// deadline significa que ela ficou aberta.
+			_, err := r.ReadString('\n')
+			session.closed = err == io.EOF
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *fakeSMTP) session(t *testing.T) smtpSession {
+	t.Helper()
+
+	select {
+	case session := <-s.sessions:
+		return session
+	case <-time.After(5 * time.Second):
+		t.Fatal("sessão SMTP não terminou")
+		return smtpSession{}
+	}
+}
+
+func TestSMTPTLSAndAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		startTLS      bool
+		mechanisms    string
+		tlsMode       string
+		authMechanism string
+		wantTLS       bool
+		wantMechanism string
+		wantErr       string
+	}{
+		{name: "STARTTLS prefere PLAIN", startTLS: true, mechanisms: "CRAM-MD5 PLAIN LOGIN", tlsMode: config.SMTPTLSStartTLS, wantTLS: true, wantMechanism: "PLAIN"},
+		{name: "STARTTLS só com LOGIN", startTLS: true, mechanisms: "LOGIN", tlsMode: config.SMTPTLSStartTLS, wantTLS: true, wantMechanism: "LOGIN"},
+		{name: "sem TLS usa CRAM-MD5", mechanisms: "PLAIN CRAM-MD5", tlsMode: config.SMTPTLSStartTLS, wantMechanism: "CRAM-MD5"},
+		{name: "mecanismo configurado", startTLS: true, mechanisms: "PLAIN LOGIN", tlsMode: config.SMTPTLSStartTLS, authMechanism: "login", wantTLS: true, wantMechanism: "LOGIN"},
+		{name: "STARTTLS obrigatório sem suporte", mechanisms: "PLAIN", tlsMode: config.SMTPTLSStartTLSRequired, wantErr: "não suporta STARTTLS"},
+		{name: "sem TLS configurado", startTLS: true, mechanisms: "CRAM-MD5", tlsMode: config.SMTPTLSNone, wantMechanism: "CRAM-MD5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeSMTP(t, tt.startTLS, tt.mechanisms)
+
+			e, err := NewEmailNotifier(server.emailConfig(tt.tlsMode, tt.authMechanism), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = e.SendAlert(context.Background(), testAlert())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("erro = %v, esperado %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SendAlert: %v", err)
+			}
+
+			session := server.session(t)
+			if session.tls != tt.wantTLS {
+				t.Errorf("TLS = %v, esperado %v", session.tls, tt.wantTLS)
+			}
+			if session.mechanism != tt.wantMechanism {
+				t.Errorf("AUTH %s, esperado %s", session.mechanism, tt.wantMechanism)
+			}
+			if !strings.Contains(session.data, "To: dba@exemplo.com") || !strings.Contains(session.data, "DATABASE MONITORING ALERT") {
+				t.Errorf("mensagem inesperada:\n%s", session.data)
+			}
+		})
+	}
+}
+
+func TestSMTPCloseAfterFailedQuit(t *testing.T) {
+	server := newFakeSMTP(t, false, "")
+	server.failQuit = true
+
+	d, err := newSMTPDialer(server.emailConfig(config.SMTPTLSNone, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := d.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Close(); err == nil {
+		t.Fatal("esperado o erro do QUIT")
+	}
+	if session := server.session(t); !session.closed {
+		t.Fatal("conexão não foi fechada após o QUIT recusado")
+	}
+}
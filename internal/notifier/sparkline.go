@@ -0,0 +1,107 @@
+package notifier
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+const (
+	sparklineWidth   = 320
+	sparklineHeight  = 80
+	sparklinePadding = 4
+)
+
+// renderSparkline desenha a série como um PNG pequeno, com a linha do
+// threshold tracejada quando ele é informado.
+func renderSparkline(values []int, threshold int, lineColor color.RGBA) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, sparklineWidth, sparklineHeight))
+	for x := 0; x < sparklineWidth; x++ {
+		for y := 0; y < sparklineHeight; y++ {
+			img.Set(x, y, color.White)
+		}
+	}
+
+	maxValue := threshold
+	for _, v := range values {
+		if v > maxValue {
+			maxValue = v
+		}
+	}
+	if maxValue == 0 {
+		maxValue = 1
+	}
+	maxValue += maxValue / 10
+
+	plotHeight := sparklineHeight - 2*sparklinePadding
+	plotWidth := sparklineWidth - 2*sparklinePadding
+
+	toY := func(v int) int {
+		return sparklineHeight - sparklinePadding - v*plotHeight/maxValue
+	}
+	toX := func(i int) int {
+		if len(values) < 2 {
+			return sparklinePadding
+		}
+		return sparklinePadding + i*plotWidth/(len(values)-1)
+	}
+
+	if threshold > 0 {
+		thresholdColor := color.RGBA{R: 150, G: 150, B: 150, A: 255}
+		y := toY(threshold)
+		for x := sparklinePadding; x < sparklineWidth-sparklinePadding; x++ {
+			if (x/4)%2 == 0 {
+				img.Set(x, y, thresholdColor)
+			}
+		}
+	}
+
+	for i := 1; i < len(values); i++ {
+		drawLine(img, toX(i-1), toY(values[i-1]), toX(i), toY(values[i]), lineColor)
+		drawLine(img, toX(i-1), toY(values[i-1])+1, toX(i), toY(values[i])+1, lineColor)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLine usa o algoritmo de Bresenham.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
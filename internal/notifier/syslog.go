@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/syslog"
+
+	"dbMonitor/internal/config"
+)
+
+// SyslogNotifier grava o alerta no syslog local ou remoto. Normalmente é o
+// último fallback da cadeia, para que o alerta fique registrado mesmo com
+// email e chat fora do ar.
+type SyslogNotifier struct {
+	writer *syslog.Writer
+}
+
+func NewSyslogNotifier(cfg config.SyslogConfig) (*SyslogNotifier, error) {
+	tag := cfg.Tag
+	if tag == "" {
+		tag = "dbmonitor"
+	}
+
+	w, err := syslog.Dial(cfg.Network, cfg.Address, syslog.LOG_WARNING|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar ao syslog: %w", err)
+	}
+
+	return &SyslogNotifier{writer: w}, nil
+}
+
+func renderSyslogLine(alert Alert) string {
+	line := fmt.Sprintf("%s: %s", alert.Subject(), alert.Message)
+	if alert.Value > 0 && alert.Threshold > 0 {
+		line = fmt.Sprintf("%s (value=%d threshold=%d)", line, alert.Value, alert.Threshold)
+	}
+	return line
+}
+
+// SendAlert só confere o contexto antes de gravar: o log/syslog não aceita
+// cancelamento, e a gravação é uma única linha.
+func (s *SyslogNotifier) SendAlert(ctx context.Context, alert Alert) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	line := renderSyslogLine(alert)
+
+	switch {
+	case alert.IsResolved():
+		return s.writer.Notice(line)
+	case alert.Severity == SeverityCritical:
+		return s.writer.Crit(line)
+	default:
+		return s.writer.Warning(line)
+	}
+}
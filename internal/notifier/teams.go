@@ -0,0 +1,187 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"dbMonitor/internal/config"
+	"dbMonitor/internal/templates"
+)
+
+// TeamsNotifier publica o alerta como Adaptive Card em um incoming webhook
+// do Microsoft Teams.
+type TeamsNotifier struct {
+	webhookURL   string
+	dashboardURL string
+	templates    *templates.Set
+	client       *http.Client
+}
+
+func NewTeamsNotifier(cfg config.TeamsConfig, tmpl *templates.Set) (*TeamsNotifier, error) {
+	if cfg.WebhookURL == "" {
+		return nil, fmt.Errorf("URL do webhook do Teams não configurada")
+	}
+	return &TeamsNotifier{
+		webhookURL:   cfg.WebhookURL,
+		dashboardURL: strings.TrimRight(cfg.DashboardURL, "/"),
+		templates:    tmpl,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Estilos de container e cores de texto do Adaptive Card por severidade.
+var teamsSeverityStyles = map[Severity][2]string{
+	SeverityWarning:  {"warning", "warning"},
+	SeverityCritical: {"attention", "attention"},
+}
+
+func (t *TeamsNotifier) renderCard(alert Alert) (map[string]interface{}, error) {
+	title := alert.Subject()
+	if subject, ok, err := t.templates.RenderSubject("teams", alert.AlertType, alert); err != nil {
+		return nil, err
+	} else if ok {
+		title = subject
+	}
+
+	text := alert.Message
+	if body, ok, err := t.templates.RenderText("teams", alert.AlertType, alert); err != nil {
+		return nil, err
+	} else if ok {
+		text = body
+	}
+
+	return renderTeamsCard(alert, title, text, t.databaseURL(alert.DatabaseName)), nil
+}
+
+// databaseURL aponta para o /stats do dbMonitor filtrado pela base.
+func (t *TeamsNotifier) databaseURL(databaseName string) string {
+	if t.dashboardURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/stats?database=%s", t.dashboardURL, url.QueryEscape(databaseName))
+}
+
+func renderTeamsCard(alert Alert, title, text, link string) map[string]interface{} {
+	style, ok := teamsSeverityStyles[alert.Severity]
+	if !ok {
+		style = [2]string{"emphasis", "default"}
+	}
+	if alert.IsResolved() {
+		style = [2]string{"good", "good"}
+	}
+
+	facts := []map[string]string{
+		{"title": "Database", "value": alert.DatabaseName},
+		{"title": "Alert Type", "value": alert.AlertType},
+		{"title": "Severity", "value": string(alert.Severity)},
+		{"title": "State", "value": string(alert.State)},
+	}
+	if alert.Value > 0 && alert.Threshold > 0 {
+		facts = append(facts, map[string]string{
+			"title": "Value / Threshold",
+			"value": fmt.Sprintf("%d / %d", alert.Value, alert.Threshold),
+		})
+	}
+	if alert.IsResolved() {
+		facts = append(facts, map[string]string{
+			"title": "Duration",
+			"value": alert.Timestamp.Sub(alert.StartedAt).Round(time.Second).String(),
+		})
+	}
+	facts = append(facts, map[string]string{
+		"title": "Timestamp",
+		"value": alert.Timestamp.Format("2006-01-02 15:04:05"),
+	})
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"msteams": map[string]string{"width": "Full"},
+		"body": []map[string]interface{}{
+			{
+				"type":  "Container",
+				"style": style[0],
+				"bleed": true,
+				"items": []map[string]interface{}{
+					{
+						"type":   "TextBlock",
+						"text":   title,
+						"weight": "Bolder",
+						"size":   "Medium",
+						"color":  style[1],
+						"wrap":   true,
+					},
+				},
+			},
+			{
+				"type": "TextBlock",
+				"text": text,
+				"wrap": true,
+			},
+			{
+				"type":  "FactSet",
+				"facts": facts,
+			},
+		},
+	}
+
+	if link != "" {
+		card["actions"] = []map[string]interface{}{
+			{
+				"type":  "Action.OpenUrl",
+				"title": "Open in dbMonitor",
+				"url":   link,
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content":     card,
+			},
+		},
+	}
+}
+
+func (t *TeamsNotifier) SendAlert(ctx context.Context, alert Alert) error {
+	payload, err := t.renderCard(alert)
+	if err != nil {
+		return err
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("falha ao montar payload do Teams: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", t.webhookURL, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("falha ao criar requisição para o Teams: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("falha ao enviar notificação para o Teams: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Webhooks do Teams respondem 200; os do Power Automate, 202.
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("erro do servidor Teams: %s", resp.Status)
+	}
+
+	log.Printf("Notificação do Teams enviada com sucesso: %s", alert.Subject())
+	return nil
+}
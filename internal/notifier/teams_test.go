@@ -0,0 +1,149 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"dbMonitor/internal/config"
+)
+
+// teamsMessage é o subconjunto do payload do Teams verificado nos testes.
+type teamsMessage struct {
+	Type        string `json:"type"`
+	Attachments []struct {
+		ContentType string `json:"contentType"`
+		Content     struct {
+			Type string `json:"type"`
+			Body []struct {
+				Type  string `json:"type"`
+				Style string `json:"style"`
+				Text  string `json:"text"`
+				Items []struct {
+					Text string `json:"text"`
+				} `json:"items"`
+				Facts []struct {
+					Title string `json:"title"`
+					Value string `json:"value"`
+				} `json:"facts"`
+			} `json:"body"`
+			Actions []struct {
+				URL string `json:"url"`
+			} `json:"actions"`
+		} `json:"content"`
+	} `json:"attachments"`
+}
+
+func TestTeamsCard(t *testing.T) {
+	resolved := testAlert()
+	resolved.State = AlertStateResolved
+	resolved.StartedAt = resolved.Timestamp.Add(-90 * time.Second)
+
+	warning := testAlert()
+	warning.Severity = SeverityWarning
+	warning.DatabaseName = "base com espaço"
+
+	tests := []struct {
+		name         string
+		alert        Alert
+		dashboardURL string
+		wantStyle    string
+		wantTitle    string
+		wantFact     string // título de um fato que precisa estar no card
+		wantLink     string
+	}{
+		{
+			name:         "critical com link para o dashboard",
+			alert:        testAlert(),
+			dashboardURL: "https://monitor.exemplo.com/",
+			wantStyle:    "attention",
+			wantTitle:    "DB Monitor CRITICAL ALERT: postgres_producao - HIGH_TOTAL_CONNECTIONS",
+			wantFact:     "Value / Threshold",
+			wantLink:     "https://monitor.exemplo.com/stats?database=postgres_producao",
+		},
+		{
+			name:         "warning com nome escapado no link",
+			alert:        warning,
+			dashboardURL: "https://monitor.exemplo.com",
+			wantStyle:    "warning",
+			wantTitle:    "DB Monitor WARNING ALERT: base com espaço - HIGH_TOTAL_CONNECTIONS",
+			wantFact:     "Severity",
+			wantLink:     "https://monitor.exemplo.com/stats?database=base+com+espa%C3%A7o",
+		},
+		{
+			name:      "resolução sem dashboard",
+			alert:     resolved,
+			wantStyle: "good",
+			wantTitle: "DB Monitor RESOLVED: postgres_producao - HIGH_TOTAL_CONNECTIONS",
+			wantFact:  "Duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Webhooks do Power Automate respondem 202.
+			server, requests := testServer(t, http.StatusAccepted, "")
+
+			n, err := NewTeamsNotifier(config.TeamsConfig{WebhookURL: server.URL, DashboardURL: tt.dashboardURL}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := n.SendAlert(context.Background(), tt.alert); err != nil {
+				t.Fatalf("SendAlert: %v", err)
+			}
+
+			var msg teamsMessage
+			if err := json.Unmarshal((<-requests).body, &msg); err != nil {
+				t.Fatalf("payload inválido: %v", err)
+			}
+			if msg.Type != "message" || len(msg.Attachments) != 1 {
+				t.Fatalf("mensagem do Teams inesperada: %+v", msg)
+			}
+			card := msg.Attachments[0].Content
+			if msg.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" || card.Type != "AdaptiveCard" {
+				t.Fatalf("anexo não é um Adaptive Card: %+v", msg.Attachments[0])
+			}
+
+			header := card.Body[0]
+			if header.Style != tt.wantStyle {
+				t.Errorf("estilo = %q, esperado %q", header.Style, tt.wantStyle)
+			}
+			if header.Items[0].Text != tt.wantTitle {
+				t.Errorf("título = %q, esperado %q", header.Items[0].Text, tt.wantTitle)
+			}
+			if card.Body[1].Text != tt.alert.Message {
+				t.Errorf("texto = %q, esperado a mensagem do alerta", card.Body[1].Text)
+			}
+
+			var facts []string
+			for _, f := range card.Body[2].Facts {
+				facts = append(facts, f.Title)
+			}
+			if !strings.Contains(strings.Join(facts, ","), tt.wantFact) {
+				t.Errorf("fatos = %v, esperado %q", facts, tt.wantFact)
+			}
+
+			switch {
+			case tt.wantLink == "" && len(card.Actions) > 0:
+				t.Errorf("card com ação sem dashboard_url: %+v", card.Actions)
+			case tt.wantLink != "" && (len(card.Actions) != 1 || card.Actions[0].URL != tt.wantLink):
+				t.Errorf("ações = %+v, esperado o link %s", card.Actions, tt.wantLink)
+			}
+		})
+	}
+}
+
+func TestTeamsError(t *testing.T) {
+	server, _ := testServer(t, http.StatusBadRequest, "Webhook message delivery failed")
+
+	n, err := NewTeamsNotifier(config.TeamsConfig{WebhookURL: server.URL}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.SendAlert(context.Background(), testAlert()); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("erro = %v, esperado o status do Teams", err)
+	}
+}
@@ -0,0 +1,146 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"dbMonitor/internal/config"
+	"dbMonitor/internal/templates"
+)
+
+const defaultSignatureHeader = "X-DBMonitor-Signature"
+
+// WebhookNotifier envia o alerta para um endpoint HTTP genérico, com corpo
+// montado a partir de um template JSON e assinatura HMAC-SHA256 opcional.
+type WebhookNotifier struct {
+	name            string
+	url             string
+	method          string
+	headers         map[string]string
+	body            *template.Template
+	secret          []byte
+	signatureHeader string
+	acceptedStatus  map[int]bool
+	client          *http.Client
+}
+
+func NewWebhookNotifier(cfg config.WebhookConfig) (*WebhookNotifier, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("URL do webhook %s não configurada", cfg.Name)
+	}
+
+	w := &WebhookNotifier{
+		name:            cfg.Name,
+		url:             cfg.URL,
+		method:          strings.ToUpper(cfg.Method),
+		headers:         cfg.Headers,
+		signatureHeader: cfg.SignatureHeader,
+		client:          &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if w.method == "" {
+		w.method = http.MethodPost
+	}
+	if w.signatureHeader == "" {
+		w.signatureHeader = defaultSignatureHeader
+	}
+	if cfg.Secret != "" {
+		w.secret = []byte(cfg.Secret)
+	}
+	if cfg.Timeout > 0 {
+		w.client.Timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+
+	if len(cfg.AcceptedStatusCodes) > 0 {
+		w.acceptedStatus = make(map[int]bool)
+		for _, code := range cfg.AcceptedStatusCodes {
+			w.acceptedStatus[code] = true
+		}
+	}
+
+	if cfg.BodyTemplate != "" {
+		body, err := templates.LoadText(cfg.BodyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao carregar template do webhook %s: %w", cfg.Name, err)
+		}
+		w.body = body
+	}
+
+	return w, nil
+}
+
+func (w *WebhookNotifier) renderBody(alert Alert) ([]byte, error) {
+	if w.body == nil {
+		return json.Marshal(alert)
+	}
+
+	var buf bytes.Buffer
+	if err := w.body.Execute(&buf, alert); err != nil {
+		return nil, fmt.Errorf("falha ao renderizar corpo do webhook %s: %w", w.name, err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("corpo do webhook %s não é um JSON válido", w.name)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// sign retorna "sha256=<hex>" do HMAC do corpo, no formato usado por GitHub
+// e afins, para que o receptor valide a origem do alerta.
+func (w *WebhookNotifier) sign(body []byte) string {
+	mac := hmac.New(sha256.New, w.secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w *WebhookNotifier) accepts(status int) bool {
+	if w.acceptedStatus == nil {
+		return status >= 200 && status < 300
+	}
+	return w.acceptedStatus[status]
+}
+
+func (w *WebhookNotifier) SendAlert(ctx context.Context, alert Alert) error {
+	body, err := w.renderBody(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, w.method, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("falha ao criar requisição para o webhook %s: %w", w.name, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.headers {
+		req.Header.Set(name, value)
+	}
+	if w.secret != nil {
+		req.Header.Set(w.signatureHeader, w.sign(body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("falha ao enviar alerta para o webhook %s: %w", w.name, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if !w.accepts(resp.StatusCode) {
+		return fmt.Errorf("erro do webhook %s: %s", w.name, resp.Status)
+	}
+
+	log.Printf("Webhook %s enviado com sucesso: %s", w.name, alert.Subject())
+	return nil
+}
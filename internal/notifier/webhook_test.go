@@ -0,0 +1,221 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dbMonitor/internal/config"
+)
+
+func writeTemplate(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "body.json.tmpl")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestWebhookSignature(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		want   string
+	}{
+		{name: "sem secret", secret: "", header: defaultSignatureHeader},
+		{name: "header padrão", secret: "segredo", header: defaultSignatureHeader},
+		{name: "header configurado", secret: "segredo", header: "X-Hub-Signature-256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := testServer(t, http.StatusOK, "")
+
+			w, err := NewWebhookNotifier(config.WebhookConfig{
+				Name:            "teste",
+				URL:             server.URL,
+				Secret:          tt.secret,
+				SignatureHeader: tt.header,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := w.SendAlert(context.Background(), testAlert()); err != nil {
+				t.Fatalf("SendAlert: %v", err)
+			}
+			req := <-requests
+
+			got := req.header.Get(tt.header)
+			if tt.secret == "" {
+				if got != "" {
+					t.Fatalf("assinatura inesperada sem secret: %q", got)
+				}
+				return
+			}
+
+			mac := hmac.New(sha256.New, []byte(tt.secret))
+			mac.Write(req.body)
+			want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			if got != want {
+				t.Fatalf("assinatura = %q, esperado %q", got, want)
+			}
+		})
+	}
+}
+
+func TestWebhookBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     map[string]interface{}
+		wantErr  string
+	}{
+		{
+			name: "sem template envia o alerta em JSON",
+			want: map[string]interface{}{
+				"database_name": "postgres_producao",
+				"alert_type":    "HIGH_TOTAL_CONNECTIONS",
+				"severity":      "critical",
+				"value":         float64(160),
+			},
+		},
+		{
+			name:     "template com funções auxiliares",
+			template: `{"title": {{json .Subject}}, "db": {{json .DatabaseName}}, "sev": "{{upper (print .Severity)}}"}`,
+			want: map[string]interface{}{
+				"title": "DB Monitor CRITICAL ALERT: postgres_producao - HIGH_TOTAL_CONNECTIONS",
+				"db":    "postgres_producao",
+				"sev":   "CRITICAL",
+			},
+		},
+		{
+			name:     "template que não gera JSON válido",
+			template: `{"db": {{.DatabaseName}}}`,
+			wantErr:  "não é um JSON válido",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := testServer(t, http.StatusOK, "")
+
+			cfg := config.WebhookConfig{Name: "teste", URL: server.URL, Method: "put"}
+			if tt.template != "" {
+				cfg.BodyTemplate = writeTemplate(t, tt.template)
+			}
+			w, err := NewWebhookNotifier(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = w.SendAlert(context.Background(), testAlert())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("erro = %v, esperado %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SendAlert: %v", err)
+			}
+
+			req := <-requests
+			if req.method != http.MethodPut {
+				t.Errorf("método = %s, esperado PUT", req.method)
+			}
+			if ct := req.header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(req.body, &got); err != nil {
+				t.Fatalf("corpo inválido: %v: %s", err, req.body)
+			}
+			for key, want := range tt.want {
+				if got[key] != want {
+					t.Errorf("%s = %v, esperado %v", key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookAcceptedStatusCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		accepted []int
+		status   int
+		wantErr  bool
+	}{
+		{name: "2xx aceito por padrão", status: http.StatusNoContent},
+		{name: "não 2xx rejeitado por padrão", status: http.StatusFound, wantErr: true},
+		{name: "código configurado", accepted: []int{200, 202}, status: http.StatusAccepted},
+		{name: "2xx fora da lista configurada", accepted: []int{200, 202}, status: http.StatusCreated, wantErr: true},
+		{name: "erro do servidor", status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := testServer(t, tt.status, "")
+
+			w, err := NewWebhookNotifier(config.WebhookConfig{
+				Name:                "teste",
+				URL:                 server.URL,
+				AcceptedStatusCodes: tt.accepted,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = w.SendAlert(context.Background(), testAlert())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("erro = %v, esperado erro: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	w, err := NewWebhookNotifier(config.WebhookConfig{Name: "teste", URL: server.URL, Timeout: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.client.Timeout != time.Second {
+		t.Fatalf("timeout = %s, esperado 1s", w.client.Timeout)
+	}
+
+	// Encurta o timeout configurado para o teste não esperar um segundo.
+	w.client.Timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	err = w.SendAlert(context.Background(), testAlert())
+	if err == nil {
+		t.Fatal("esperado erro de timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("SendAlert levou %s, esperado o timeout do cliente", elapsed)
+	}
+}
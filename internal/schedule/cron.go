@@ -0,0 +1,133 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron é uma expressão de cinco campos (minuto, hora, dia do mês, mês e dia
+// da semana) no formato do crontab: "*", listas "1,15", intervalos "1-5" e
+// passos "*/10" ou "0-30/5". O dia da semana aceita 0 ou 7 para domingo.
+type Cron struct {
+	minute, hour, dom, month, dow []bool
+
+	domRestricted, dowRestricted bool
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minuto", 0, 59},
+	{"hora", 0, 23},
+	{"dia do mês", 1, 31},
+	{"mês", 1, 12},
+	{"dia da semana", 0, 7},
+}
+
+func Parse(expr string) (*Cron, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("expressão cron deve ter 5 campos: %q", expr)
+	}
+
+	sets := make([][]bool, len(fields))
+	for i, f := range fields {
+		set, err := parseField(parts[i], f)
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+
+	// Domingo pode ser 0 ou 7.
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return &Cron{
+		minute:        sets[0],
+		hour:          sets[1],
+		dom:           sets[2],
+		month:         sets[3],
+		dow:           sets[4],
+		domRestricted: !strings.HasPrefix(parts[2], "*"),
+		dowRestricted: !strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+func parseField(expr string, f field) ([]bool, error) {
+	set := make([]bool, f.max+1)
+
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			rangeExpr = item[:i]
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("passo inválido no campo %s: %q", f.name, item)
+			}
+		}
+
+		lo, hi := f.min, f.max
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("valor inválido no campo %s: %q", f.name, item)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("valor inválido no campo %s: %q", f.name, item)
+				}
+			} else if step > 1 {
+				hi = f.max
+			}
+		}
+
+		if lo < f.min || hi > f.max || lo > hi {
+			return nil, fmt.Errorf("valor fora do intervalo %d-%d no campo %s: %q", f.min, f.max, f.name, item)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+
+	return set, nil
+}
+
+// Matches indica se o minuto de t casa com a expressão. Como no cron, quando
+// dia do mês e dia da semana são restritos, basta um deles casar; um campo que
+// começa com "*" (inclusive "*/2") não conta como restrito.
+func (c *Cron) Matches(t time.Time) bool {
+	if !c.minute[t.Minute()] || !c.hour[t.Hour()] || !c.month[int(t.Month())] {
+		return false
+	}
+
+	domMatch := c.dom[t.Day()]
+	dowMatch := c.dow[int(t.Weekday())]
+
+	if c.domRestricted && c.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// ActiveWindow procura um início de janela que case com a expressão nos
+// últimos duration (inclusive o minuto atual) e retorna o mais recente.
+func (c *Cron) ActiveWindow(t time.Time, duration time.Duration) (time.Time, bool) {
+	current := t.Truncate(time.Minute)
+	for start := current; t.Sub(start) < duration; start = start.Add(-time.Minute) {
+		if c.Matches(start) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
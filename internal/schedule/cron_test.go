@@ -0,0 +1,195 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "todos os campos", expr: "* * * * *"},
+		{name: "listas, intervalos e passos", expr: "0-30/5 9-17 1,15 */3 1-5"},
+		{name: "passo a partir de um valor", expr: "5/10 * * * *"},
+		{name: "domingo como 7", expr: "0 0 * * 7"},
+		{name: "campos a menos", expr: "* * * *", wantErr: true},
+		{name: "campos a mais", expr: "* * * * * *", wantErr: true},
+		{name: "minuto fora do intervalo", expr: "60 * * * *", wantErr: true},
+		{name: "dia do mês zero", expr: "* * 0 * *", wantErr: true},
+		{name: "intervalo invertido", expr: "* 17-9 * * *", wantErr: true},
+		{name: "passo zero", expr: "*/0 * * * *", wantErr: true},
+		{name: "valor não numérico", expr: "* * * jan *", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) erro = %v, esperado erro: %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	// 16/10/2026 é uma sexta-feira.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		t    time.Time
+		want bool
+	}{
+		{name: "passo casa", expr: "*/15 * * * *", t: at(16, 10, 30), want: true},
+		{name: "passo não casa", expr: "*/15 * * * *", t: at(16, 10, 31)},
+		{name: "passo a partir de um valor", expr: "5/10 * * * *", t: at(16, 10, 25), want: true},
+		{name: "horário comercial em dia útil", expr: "0 9-17 * * 1-5", t: at(16, 9, 0), want: true},
+		{name: "horário comercial no sábado", expr: "0 9-17 * * 1-5", t: at(17, 9, 0)},
+		{name: "domingo como 7", expr: "0 0 * * 7", t: at(18, 0, 0), want: true},
+		{name: "domingo como 0", expr: "0 0 * * 0", t: at(18, 0, 0), want: true},
+		{name: "dia do mês e da semana: casa pela semana", expr: "0 0 1 * 1", t: at(19, 0, 0), want: true},
+		{name: "dia do mês e da semana: casa pelo mês", expr: "0 0 1 * 1", t: at(1, 0, 0), want: true},
+		{name: "dia do mês e da semana: nenhum casa", expr: "0 0 1 * 1", t: at(20, 0, 0)},
+		{name: "só dia do mês restrito", expr: "0 0 1 * *", t: at(2, 0, 0)},
+		{name: "dia do mês com passo não conta como restrito", expr: "0 0 */2 * 1", t: at(19, 0, 0), want: true},
+		{name: "dia do mês com passo exige a semana", expr: "0 0 */2 * 1", t: at(17, 0, 0)},
+		{name: "mês fora da lista", expr: "0 8 * 1,7 *", t: at(16, 8, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Matches(tt.t); got != tt.want {
+				t.Fatalf("Matches(%s) = %v, esperado %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActiveWindow(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	// Os instantes são informados em UTC e avaliados no fuso do caso, para
+	// que os horários ambíguos da virada do horário de verão não dependam de
+	// time.Date. Em Nova York, 08/03/2026 pula de 02:00 EST para 03:00 EDT
+	// e 01/11/2026 volta de 02:00 EDT para 01:00 EST; São Paulo não tem
+	// horário de verão (UTC-3).
+	tests := []struct {
+		name      string
+		expr      string
+		location  string
+		now       time.Time
+		duration  time.Duration
+		wantStart time.Time
+	}{
+		{
+			name:      "dentro da janela",
+			expr:      "0 2 * * *",
+			location:  "UTC",
+			now:       utc(2026, 10, 16, 2, 59),
+			duration:  time.Hour,
+			wantStart: utc(2026, 10, 16, 2, 0),
+		},
+		{
+			name:     "fim da janela é exclusivo",
+			expr:     "0 2 * * *",
+			location: "UTC",
+			now:      utc(2026, 10, 16, 3, 0),
+			duration: time.Hour,
+		},
+		{
+			name:      "janela que atravessa a meia-noite",
+			expr:      "30 23 * * *",
+			location:  "UTC",
+			now:       utc(2026, 10, 17, 0, 15),
+			duration:  time.Hour,
+			wantStart: utc(2026, 10, 16, 23, 30),
+		},
+		{
+			name:      "sábado 22h em São Paulo",
+			expr:      "0 22 * * 6",
+			location:  "America/Sao_Paulo",
+			now:       utc(2026, 10, 18, 4, 30), // domingo 01:30 -03
+			duration:  4 * time.Hour,
+			wantStart: utc(2026, 10, 18, 1, 0), // sábado 22:00 -03
+		},
+		{
+			name:     "mesmo instante avaliado em UTC",
+			expr:     "0 22 * * 6",
+			location: "UTC",
+			now:      utc(2026, 10, 18, 4, 30),
+			duration: 4 * time.Hour,
+		},
+		{
+			name:     "horário inexistente no início do horário de verão",
+			expr:     "30 2 * * *",
+			location: "America/New_York",
+			now:      utc(2026, 3, 8, 7, 45), // 03:45 EDT
+			duration: time.Hour,
+		},
+		{
+			name:      "duração conta tempo decorrido, não horário de parede",
+			expr:      "0 1 * * *",
+			location:  "America/New_York",
+			now:       utc(2026, 3, 8, 7, 30), // 03:30 EDT, 90min após 01:00 EST
+			duration:  2 * time.Hour,
+			wantStart: utc(2026, 3, 8, 6, 0),
+		},
+		{
+			name:     "janela termina após a duração no início do horário de verão",
+			expr:     "0 1 * * *",
+			location: "America/New_York",
+			now:      utc(2026, 3, 8, 8, 0), // 04:00 EDT
+			duration: 2 * time.Hour,
+		},
+		{
+			name:      "primeira 01:00 no fim do horário de verão",
+			expr:      "0 1 * * *",
+			location:  "America/New_York",
+			now:       utc(2026, 11, 1, 5, 30), // 01:30 EDT
+			duration:  90 * time.Minute,
+			wantStart: utc(2026, 11, 1, 5, 0), // 01:00 EDT
+		},
+		{
+			name:      "horário repetido reabre a janela",
+			expr:      "0 1 * * *",
+			location:  "America/New_York",
+			now:       utc(2026, 11, 1, 6, 15), // 01:15 EST
+			duration:  90 * time.Minute,
+			wantStart: utc(2026, 11, 1, 6, 0), // 01:00 EST
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, err := time.LoadLocation(tt.location)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start, active := c.ActiveWindow(tt.now.In(location), tt.duration)
+			if wantActive := !tt.wantStart.IsZero(); active != wantActive {
+				t.Fatalf("ativa = %v, esperado %v (início %s)", active, wantActive, start)
+			}
+			if active && !start.Equal(tt.wantStart) {
+				t.Fatalf("início = %s, esperado %s", start.UTC(), tt.wantStart)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// DefaultKey é a entrada usada quando não há templates para o tipo do alerta.
+const DefaultKey = "default"
+
+// Files aponta para os arquivos de template de um canal e tipo de alerta.
+// Subject e Text usam text/template; HTML usa html/template.
+type Files struct {
+	Subject string `yaml:"subject"`
+	Text    string `yaml:"text"`
+	HTML    string `yaml:"html"`
+}
+
+type entry struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// samples são os dados com que cada template é executado ao ser carregado.
+var samples []interface{}
+
+// RegisterSamples define os dados de exemplo executados pelo Load e pelo
+// LoadText. O pacote notifier registra um alerta disparado e um resolvido,
+// para que um campo inexistente ou uma função chamada com o tipo errado seja
+// rejeitado ao carregar a configuração, e não no envio do alerta.
+func RegisterSamples(data ...interface{}) {
+	samples = append(samples, data...)
+}
+
+// executor é implementado pelos templates de text/template e html/template.
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+func check(t executor) error {
+	for _, data := range samples {
+		if err := t.Execute(io.Discard, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set guarda os templates já carregados, indexados por canal e tipo de alerta.
+type Set struct {
+	channels map[string]map[string]*entry
+}
+
+// Load carrega e valida todos os templates configurados, no formato
+// canal -> tipo de alerta (ou "default") -> arquivos. Cada template é
+// executado com os dados de exemplo registrados.
+func Load(cfg map[string]map[string]Files) (*Set, error) {
+	set := &Set{channels: make(map[string]map[string]*entry)}
+
+	for channel, alertTypes := range cfg {
+		set.channels[channel] = make(map[string]*entry)
+
+		for alertType, files := range alertTypes {
+			e := &entry{}
+			var err error
+
+			if files.Subject != "" {
+				if e.subject, err = parseText(files.Subject); err != nil {
+					return nil, fmt.Errorf("template de assunto %s/%s: %w", channel, alertType, err)
+				}
+			}
+			if files.Text != "" {
+				if e.text, err = parseText(files.Text); err != nil {
+					return nil, fmt.Errorf("template de texto %s/%s: %w", channel, alertType, err)
+				}
+			}
+			if files.HTML != "" {
+				if e.html, err = parseHTML(files.HTML); err != nil {
+					return nil, fmt.Errorf("template HTML %s/%s: %w", channel, alertType, err)
+				}
+			}
+
+			set.channels[channel][alertType] = e
+		}
+	}
+
+	return set, nil
+}
+
+// LoadText carrega um único arquivo de text/template com as funções
+// auxiliares, para canais que não seguem o esquema assunto/texto/HTML.
+func LoadText(path string) (*texttemplate.Template, error) {
+	return parseText(path)
+}
+
+func parseText(path string) (*texttemplate.Template, error) {
+	t, err := texttemplate.New(filepath.Base(path)).Funcs(FuncMap()).ParseFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := check(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func parseHTML(path string) (*htmltemplate.Template, error) {
+	t, err := htmltemplate.New(filepath.Base(path)).Funcs(FuncMap()).ParseFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := check(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *Set) lookup(channel, alertType string, pick func(*entry) bool) *entry {
+	if s == nil {
+		return nil
+	}
+	alertTypes, ok := s.channels[channel]
+	if !ok {
+		return nil
+	}
+	if e, ok := alertTypes[alertType]; ok && pick(e) {
+		return e
+	}
+	if e, ok := alertTypes[DefaultKey]; ok && pick(e) {
+		return e
+	}
+	return nil
+}
+
+// RenderSubject retorna ok=false quando não há template de assunto para o
+// canal, permitindo que o notifier use o seu formato padrão.
+func (s *Set) RenderSubject(channel, alertType string, data interface{}) (string, bool, error) {
+	e := s.lookup(channel, alertType, func(e *entry) bool { return e.subject != nil })
+	if e == nil {
+		return "", false, nil
+	}
+
+	var buf bytes.Buffer
+	if err := e.subject.Execute(&buf, data); err != nil {
+		return "", true, fmt.Errorf("falha ao renderizar assunto: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), true, nil
+}
+
+func (s *Set) RenderText(channel, alertType string, data interface{}) (string, bool, error) {
+	e := s.lookup(channel, alertType, func(e *entry) bool { return e.text != nil })
+	if e == nil {
+		return "", false, nil
+	}
+
+	var buf bytes.Buffer
+	if err := e.text.Execute(&buf, data); err != nil {
+		return "", true, fmt.Errorf("falha ao renderizar texto: %w", err)
+	}
+	return buf.String(), true, nil
+}
+
+func (s *Set) RenderHTML(channel, alertType string, data interface{}) (string, bool, error) {
+	e := s.lookup(channel, alertType, func(e *entry) bool { return e.html != nil })
+	if e == nil {
+		return "", false, nil
+	}
+
+	var buf bytes.Buffer
+	if err := e.html.Execute(&buf, data); err != nil {
+		return "", true, fmt.Errorf("falha ao renderizar HTML: %w", err)
+	}
+	return buf.String(), true, nil
+}
+
+// FuncMap são as funções auxiliares disponíveis em todos os templates.
+func FuncMap() map[string]interface{} {
+	return map[string]interface{}{
+		"humanizeDuration": HumanizeDuration,
+		"humanizeBytes":    HumanizeBytes,
+		"since":            func(t time.Time) time.Duration { return time.Since(t) },
+		"formatTime":       func(t time.Time, layout string) string { return t.Format(layout) },
+		"upper":            strings.ToUpper,
+		"lower":            strings.ToLower,
+		"join":             strings.Join,
+		"json":             toJSON,
+	}
+}
+
+// toJSON serializa o valor como JSON, útil para montar payloads de webhook
+// sem se preocupar com escape de aspas e quebras de linha.
+func toJSON(value interface{}) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// HumanizeDuration formata uma duração (time.Duration ou segundos) como
+// "2h 5m 3s".
+func HumanizeDuration(value interface{}) string {
+	var d time.Duration
+	switch v := value.(type) {
+	case time.Duration:
+		d = v
+	case int:
+		d = time.Duration(v) * time.Second
+	case int64:
+		d = time.Duration(v) * time.Second
+	case float64:
+		d = time.Duration(v * float64(time.Second))
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+
+	d = d.Round(time.Second)
+	if d < time.Second {
+		return "0s"
+	}
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	if seconds > 0 {
+		parts = append(parts, fmt.Sprintf("%ds", seconds))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// HumanizeBytes formata um tamanho em bytes usando unidades binárias.
+func HumanizeBytes(value interface{}) string {
+	var b float64
+	switch v := value.(type) {
+	case int:
+		b = float64(v)
+	case int64:
+		b = float64(v)
+	case float64:
+		b = v
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	i := 0
+	for b >= 1024 && i < len(units)-1 {
+		b /= 1024
+		i++
+	}
+
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", b, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", b, units[i])
+}
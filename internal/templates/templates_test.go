@@ -0,0 +1,91 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type sampleAlert struct {
+	DatabaseName string
+	Value        int
+}
+
+func (a sampleAlert) IsResolved() bool {
+	return false
+}
+
+func init() {
+	RegisterSamples(sampleAlert{DatabaseName: "exemplo", Value: 160})
+}
+
+func TestLoadExecutesTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   func(path string) Files
+		content string
+		wantErr string
+	}{
+		{
+			name:    "template válido",
+			files:   func(path string) Files { return Files{Subject: path} },
+			content: `{{if .IsResolved}}ok{{end}} {{upper .DatabaseName}} {{.Value}}`,
+		},
+		{
+			name:    "erro de sintaxe",
+			files:   func(path string) Files { return Files{Text: path} },
+			content: `{{.DatabaseName`,
+			wantErr: "template de texto email/default",
+		},
+		{
+			name:    "campo inexistente",
+			files:   func(path string) Files { return Files{Text: path} },
+			content: `{{.Foo}}`,
+			wantErr: "can't evaluate field Foo",
+		},
+		{
+			name:    "função com tipo errado",
+			files:   func(path string) Files { return Files{Subject: path} },
+			content: `{{upper .Value}}`,
+			wantErr: "wrong type",
+		},
+		{
+			name:    "campo inexistente no HTML",
+			files:   func(path string) Files { return Files{HTML: path} },
+			content: `<p>{{.Foo}}</p>`,
+			wantErr: "template HTML email/default",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "alerta.tmpl")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := Load(map[string]map[string]Files{"email": {DefaultKey: tt.files(path)}})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("erro = %v, esperado %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadTextExecutesTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhook.json.tmpl")
+	if err := os.WriteFile(path, []byte(`{"db": {{json .Database}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadText(path); err == nil || !strings.Contains(err.Error(), "can't evaluate field Database") {
+		t.Fatalf("erro = %v, esperado campo inexistente", err)
+	}
+}
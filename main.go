@@ -4,39 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"text/template"
 	"time"
 
 	"dbMonitor/internal/config"
+	"dbMonitor/internal/database"
 	"dbMonitor/internal/monitor"
 	"dbMonitor/internal/notifier"
 )
 
-// Definição do template de email para maior flexibilidade
-const emailTemplate = `
-DATABASE MONITORING ALERT
-
-Database: {{.DatabaseName}}
-Alert Type: {{.AlertType}}
-Message: {{.Message}}
-{{if .Value}}Current Value: {{.Value}}{{end}}
-{{if .Threshold}}Configured Threshold: {{.Threshold}}{{end}}
-Timestamp: {{.Timestamp}}
-
-This is an automated alert from the database monitoring system.
-Please check the database status immediately.
-
-Connection Pool Information:
-- Pool connections are managed automatically
-- Unhealthy connections are automatically recreated
-- Health checks run every {{.HealthCheckInterval}} seconds
-`
-
 func main() {
 	// Load configuration
 	cfg, err := config.Load("config.yaml")
@@ -44,20 +25,113 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Initialize notifiers
-	var notifiers []notifier.Notifier
-	emailNotifier := notifier.NewEmailNotifier(cfg.Email)
-	notifiers = append(notifiers, emailNotifier)
+	alertTemplates := cfg.AlertTemplates()
+
+	// Initialize notifiers as named channels (timeout, fallback and health)
+	channels := notifier.NewChannelSet(cfg.Channels)
+
+	type channelRoute struct {
+		name       string
+		severities []string
+	}
+	var routes []channelRoute
+
+	addChannel := func(name string, n notifier.Notifier, severities []string) {
+		channels.Add(name, n)
+		routes = append(routes, channelRoute{name: name, severities: severities})
+	}
 
-	if cfg.Slack.WebhookURL != "" {
-		slackNotifier, err := notifier.NewSlackNotifier(cfg.Slack)
+	emailNotifier, err := notifier.NewEmailNotifier(cfg.Email, alertTemplates)
+	if err != nil {
+		log.Fatalf("Failed to initialize email notifier: %v", err)
+	}
+	addChannel("email", emailNotifier, cfg.Email.Severities)
+
+	if cfg.Slack.WebhookURL != "" || cfg.Slack.BotToken != "" {
+		slackNotifier, err := notifier.NewSlackNotifier(cfg.Slack, alertTemplates)
 		if err == nil {
-			notifiers = append(notifiers, slackNotifier)
+			addChannel("slack", slackNotifier, cfg.Slack.Severities)
 		} else {
 			log.Printf("Warning: Failed to initialize Slack notifier: %v", err)
 		}
 	}
-	multiNotifier := notifier.NewMultiNotifier(notifiers...)
+
+	if cfg.Teams.WebhookURL != "" {
+		teamsNotifier, err := notifier.NewTeamsNotifier(cfg.Teams, alertTemplates)
+		if err == nil {
+			addChannel("teams", teamsNotifier, cfg.Teams.Severities)
+		} else {
+			log.Printf("Warning: Failed to initialize Teams notifier: %v", err)
+		}
+	}
+
+	if cfg.PagerDuty.RoutingKey != "" {
+		pagerDutyNotifier, err := notifier.NewPagerDutyNotifier(cfg.PagerDuty)
+		if err == nil {
+			addChannel("pagerduty", pagerDutyNotifier, cfg.PagerDuty.Severities)
+		} else {
+			log.Printf("Warning: Failed to initialize PagerDuty notifier: %v", err)
+		}
+	}
+
+	for _, webhookCfg := range cfg.Webhooks {
+		webhookNotifier, err := notifier.NewWebhookNotifier(webhookCfg)
+		if err != nil {
+			log.Printf("Warning: Failed to initialize webhook %s: %v", webhookCfg.Name, err)
+			continue
+		}
+		addChannel("webhook-"+webhookCfg.Name, webhookNotifier, webhookCfg.Severities)
+	}
+
+	if cfg.Syslog.Enabled {
+		syslogNotifier, err := notifier.NewSyslogNotifier(cfg.Syslog)
+		if err != nil {
+			log.Printf("Warning: Failed to initialize syslog notifier: %v", err)
+		} else if cfg.Syslog.FallbackOnly {
+			channels.Add("syslog", syslogNotifier)
+		} else {
+			addChannel("syslog", syslogNotifier, cfg.Syslog.Severities)
+		}
+	}
+
+	// Put channels behind persistent delivery queues when enabled
+	queues := notifier.NewQueueManager(cfg.NotificationQueue)
+	defer queues.Close()
+
+	deliveries := make(map[string]notifier.Notifier)
+	for _, name := range channels.Names() {
+		channel, _ := channels.Get(name)
+		var n notifier.Notifier = channel
+		if cfg.NotificationQueue.Enabled {
+			queued, err := queues.Wrap(name, channel)
+			if err != nil {
+				log.Fatalf("Failed to initialize notification queue for %s: %v", name, err)
+			}
+			n = queued
+		}
+		deliveries[name] = n
+	}
+
+	// Fallbacks go through the fallback channel's queue as well; a queued
+	// channel only falls back after exhausting its retries
+	channels.LinkFallbacks(deliveries)
+
+	// Route alerts through the routing tree, or by channel severities when
+	// no route is configured
+	var alertNotifier notifier.Notifier
+	if cfg.Route != nil {
+		tree, err := notifier.NewRoutingTree(*cfg.Route, cfg.Receivers, deliveries)
+		if err != nil {
+			log.Fatalf("Failed to initialize routing tree: %v", err)
+		}
+		alertNotifier = tree
+	} else {
+		router := notifier.NewSeverityRouter()
+		for _, r := range routes {
+			router.Route(deliveries[r.name], r.severities...)
+		}
+		alertNotifier = router
+	}
 
 	// Test email connection
 	if err := emailNotifier.TestConnection(); err != nil {
@@ -67,7 +141,7 @@ func main() {
 	}
 
 	// Initialize database monitor with connection pool
-	dbMonitor := monitor.NewDatabaseMonitor(cfg, multiNotifier)
+	dbMonitor := monitor.NewDatabaseMonitor(cfg, alertNotifier)
 	defer dbMonitor.Close()
 
 	// Setup context for graceful shutdown
@@ -85,7 +159,7 @@ func main() {
 	}()
 
 	// Start HTTP server for monitoring endpoints
-	go startHTTPServer(dbMonitor, cfg.Application.HTTPServerAddress)
+	go startHTTPServer(dbMonitor, channels, queues, cfg.Application.HTTPServerAddress)
 
 	log.Println("Starting database monitoring with connection pooling...")
 
@@ -102,10 +176,6 @@ func main() {
 	healthTicker := time.NewTicker(time.Duration(cfg.Application.HealthCheckInterval) * time.Second)
 	defer healthTicker.Stop()
 
-	// Setup alert reset ticker
-	alertResetTicker := time.NewTicker(time.Duration(cfg.Application.AlertResetInterval) * time.Second)
-	defer alertResetTicker.Stop()
-
 	for {
 		select {
 		case <-ctx.Done():
@@ -130,15 +200,11 @@ func main() {
 			}
 			log.Printf("Health check complete: %d/%d databases healthy",
 				healthyCount, len(healthResults))
-
-		case <-alertResetTicker.C:
-			log.Println("Resetting alert counts...")
-			dbMonitor.ResetAlertCounts()
 		}
 	}
 }
 
-func startHTTPServer(dbMonitor *monitor.DatabaseMonitor, address string) {
+func startHTTPServer(dbMonitor *monitor.DatabaseMonitor, channels *notifier.ChannelSet, queues *notifier.QueueManager, address string) {
 	mux := http.NewServeMux()
 
 	// Health endpoint
@@ -161,6 +227,14 @@ func startHTTPServer(dbMonitor *monitor.DatabaseMonitor, address string) {
 	// Stats endpoint
 	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
 		stats := dbMonitor.GetLastStats()
+		if name := r.URL.Query().Get("database"); name != "" {
+			dbStats, ok := stats[name]
+			if !ok {
+				http.Error(w, "Database not found", http.StatusNotFound)
+				return
+			}
+			stats = map[string]*database.SessionStats{name: dbStats}
+		}
 
 		response := map[string]interface{}{
 			"timestamp": time.Now().Format(time.RFC3339),
@@ -204,25 +278,185 @@ func startHTTPServer(dbMonitor *monitor.DatabaseMonitor, address string) {
 		json.NewEncoder(w).Encode(response)
 	})
 
-	// Reset alerts endpoint (POST only)
-	mux.HandleFunc("/reset-alerts", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// Reset alerts endpoint
+	mux.HandleFunc("POST /reset-alerts", func(w http.ResponseWriter, r *http.Request) {
+		dbMonitor.ResetAlerts()
+
+		response := map[string]interface{}{
+			"status":    "success",
+			"message":   "Alert states reset",
+			"timestamp": time.Now().Format(time.RFC3339),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+
+	// Alert states endpoint
+	mux.HandleFunc("/alerts", func(w http.ResponseWriter, r *http.Request) {
+		alerts := dbMonitor.GetAlerts()
+
+		response := map[string]interface{}{
+			"timestamp": time.Now().Format(time.RFC3339),
+			"alerts":    alerts,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+
+	// Alert acknowledgement endpoint
+	mux.HandleFunc("POST /alerts/{id}/ack", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Author          string     `json:"author"`
+			Comment         string     `json:"comment"`
+			ExpiresAt       *time.Time `json:"expires_at"`
+			DurationMinutes int        `json:"duration_minutes"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON body: "+err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		dbMonitor.ResetAlertCounts()
+		expiresAt := req.ExpiresAt
+		if expiresAt == nil && req.DurationMinutes > 0 {
+			t := time.Now().Add(time.Duration(req.DurationMinutes) * time.Minute)
+			expiresAt = &t
+		}
+
+		status, err := dbMonitor.AckAlert(r.PathValue("id"), req.Author, req.Comment, expiresAt)
+		switch {
+		case errors.Is(err, monitor.ErrAlertNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case errors.Is(err, monitor.ErrAlertResolved):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+	})
+
+	// Silences endpoint (GET lists, POST creates)
+	mux.HandleFunc("/silences", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			response := map[string]interface{}{
+				"timestamp":  time.Now().Format(time.RFC3339),
+				"silences":   dbMonitor.GetSilences(),
+				"suppressed": dbMonitor.GetSuppressedAlerts(),
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+
+		case http.MethodPost:
+			var req struct {
+				monitor.Silence
+				DurationMinutes int `json:"duration_minutes"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			silence := req.Silence
+			if silence.EndsAt.IsZero() && req.DurationMinutes > 0 {
+				start := silence.StartsAt
+				if start.IsZero() {
+					start = time.Now()
+				}
+				silence.EndsAt = start.Add(time.Duration(req.DurationMinutes) * time.Minute)
+			}
+
+			created, err := dbMonitor.AddSilence(silence)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(created)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("DELETE /silences/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !dbMonitor.DeleteSilence(r.PathValue("id")) {
+			http.Error(w, "Silence not found", http.StatusNotFound)
+			return
+		}
 
 		response := map[string]interface{}{
 			"status":    "success",
-			"message":   "Alert counts reset",
+			"message":   "Silence deleted",
+			"timestamp": time.Now().Format(time.RFC3339),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+
+	// Blocking sessions endpoint
+	mux.HandleFunc("/blocking", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		trees := dbMonitor.GetBlockingTrees(ctx)
+
+		response := map[string]interface{}{
 			"timestamp": time.Now().Format(time.RFC3339),
+			"databases": trees,
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(response)
 	})
 
+	// Notification channel health endpoint
+	mux.HandleFunc("/notifiers", func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"timestamp": time.Now().Format(time.RFC3339),
+			"channels":  channels.Health(),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+
+	// Notification queues endpoint
+	mux.HandleFunc("/notification-queues", func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"timestamp": time.Now().Format(time.RFC3339),
+			"queues":    queues.Stats(),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+
+	// Prometheus metrics endpoint
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		var buf bytes.Buffer
+		if err := dbMonitor.WriteMetrics(ctx, &buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write(buf.Bytes())
+	})
+
 	server := &http.Server{
 		Addr:         address,
 		Handler:      mux,
@@ -234,10 +468,19 @@ func startHTTPServer(dbMonitor *monitor.DatabaseMonitor, address string) {
 	log.Printf("Starting HTTP server on %s...", address)
 	log.Println("Available endpoints:")
 	log.Println("  GET  /health      - Database health check")
-	log.Println("  GET  /stats       - Last session statistics")
+	log.Println("  GET  /stats       - Last session statistics (?database=<name> to filter)")
 	log.Println("  GET  /pool-stats  - Connection pool statistics")
-	log.Println("  GET  /alert-counts - Alert counts")
-	log.Println("  POST /reset-alerts - Reset alert counts")
+	log.Println("  GET  /alert-counts - Occurrences of active alerts")
+	log.Println("  GET  /alerts      - Alert states (pending, firing, resolved) and acks")
+	log.Println("  POST /alerts/{id}/ack - Acknowledge an alert")
+	log.Println("  POST /reset-alerts - Clear alert states")
+	log.Println("  GET  /silences    - Active silences and suppressed notifications")
+	log.Println("  POST /silences    - Create a silence")
+	log.Println("  DELETE /silences/{id} - Delete a silence")
+	log.Println("  GET  /blocking    - Current lock blocking trees")
+	log.Println("  GET  /notifiers   - Notification channel health")
+	log.Println("  GET  /notification-queues - Notification queue depth and failures")
+	log.Println("  GET  /metrics     - Prometheus metrics")
 
 	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		log.Printf("HTTP server error: %v", err)